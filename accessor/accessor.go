@@ -0,0 +1,294 @@
+// Package accessor generates getters, setters, builders and other helper
+// methods for structs with unexported fields.
+package accessor
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+	"golang.org/x/tools/imports"
+)
+
+// MarkerComment marks a struct type for generation when Config.Types is empty.
+// Options may follow the marker, e.g. "//accessor:generate -builder".
+const MarkerComment = "//accessor:generate"
+
+// ErrNoAnnotatedTypes is returned by GenerateFiles when Config.Types is empty
+// and no type of the selected packages is annotated with MarkerComment.
+var ErrNoAnnotatedTypes = errors.New("no types annotated with " + MarkerComment)
+
+// Options controls which methods are generated for a type. The same flags are
+// accepted on the command line and after MarkerComment.
+type Options struct {
+	Setter           bool
+	ValidatedSetters bool
+	Builder          bool
+	With             bool
+	Equal            bool
+	Clone            bool
+	JSON             bool
+	Text             bool
+}
+
+// RegisterFlags registers the flags setting o, using the current values of o as defaults.
+func (o *Options) RegisterFlags(fs *flag.FlagSet) {
+	fs.BoolVar(&o.Setter, "setter", o.Setter, "generate setter")
+	fs.BoolVar(&o.ValidatedSetters, "validated-setters", o.ValidatedSetters, "make setters return error and keep the old value unless validate method passes")
+	fs.BoolVar(&o.Builder, "builder", o.Builder, "generate builder")
+	fs.BoolVar(&o.With, "with", o.With, "generate With methods returning a modified copy")
+	fs.BoolVar(&o.Equal, "equal", o.Equal, "generate Equal method")
+	fs.BoolVar(&o.Clone, "clone", o.Clone, "generate deep Clone method")
+	fs.BoolVar(&o.JSON, "json", o.JSON, "generate MarshalJSON and UnmarshalJSON methods")
+	fs.BoolVar(&o.Text, "text", o.Text, "generate MarshalText and UnmarshalText methods for single field struct")
+}
+
+// Config selects the types to generate and how.
+type Config struct {
+	// Dir is the directory in which Patterns are resolved. default: current directory.
+	Dir string
+	// Patterns selects the packages, as accepted by go list. default: ".".
+	Patterns []string
+	// Types lists the types to generate, e.g. "Customer" or "Pair[K, V]". They
+	// must belong to a single package. When empty, every struct annotated with
+	// MarkerComment in the selected packages is generated.
+	Types []string
+	// Options applies to Types, or is overridden by the options following the
+	// marker of annotated types.
+	Options Options
+	// Command is written in the "Code generated by" header of Types.
+	// default: the -type flag and the flags of Options.
+	Command string
+	// Output is the path of the generated file when Types has a single type.
+	// It cannot be used without Types.
+	// default: srcdir/{type}_accessor.go
+	Output string
+}
+
+// File is a generated file.
+type File struct {
+	Path     string
+	TypeName string
+	Source   []byte
+}
+
+// Generate returns the generated source of the single type of cfg.Types.
+func Generate(cfg Config) ([]byte, error) {
+	if len(cfg.Types) != 1 {
+		return nil, fmt.Errorf("generate requires a single type, got %d", len(cfg.Types))
+	}
+	files, err := GenerateFiles(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return files[0].Source, nil
+}
+
+// GenerateFiles generates a file for each type selected by cfg.
+func GenerateFiles(cfg Config) ([]File, error) {
+	patterns := cfg.Patterns
+	if len(patterns) == 0 {
+		patterns = []string{"."}
+	}
+	pkgs, err := loadPackages(cfg.Dir, patterns)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(cfg.Types) == 0 {
+		if cfg.Output != "" {
+			return nil, fmt.Errorf("output requires types")
+		}
+		return generateAnnotated(pkgs, patterns, cfg.Options)
+	}
+
+	if len(pkgs) != 1 {
+		return nil, fmt.Errorf("%d packages found, types require exactly one package", len(pkgs))
+	}
+	if len(cfg.Types) > 1 && cfg.Output != "" {
+		return nil, fmt.Errorf("output cannot be used with multiple types")
+	}
+
+	gen := generator{
+		options:  cfg.Options,
+		base:     cfg.Options,
+		explicit: make(map[string]bool),
+		pkg:      pkgs[0],
+	}
+	for _, name := range cfg.Types {
+		gen.explicit[baseTypeName(name)] = true
+	}
+
+	command := cfg.Command
+	if command == "" {
+		command = strings.Join(append([]string{"-type=" + strings.Join(cfg.Types, ",")}, cfg.Options.args()...), " ")
+	}
+
+	var files []File
+	for _, name := range cfg.Types {
+		output := cfg.Output
+		if output == "" {
+			output = outputPath(packageDir(pkgs[0]), name)
+		}
+		file, err := gen.generateFile(output, name, command)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, file)
+	}
+	return files, nil
+}
+
+func generateAnnotated(pkgs []*packages.Package, patterns []string, base Options) ([]File, error) {
+	var files []File
+	for _, pkg := range pkgs {
+		gen := generator{base: base, pkg: pkg}
+		for _, annotated := range annotatedTypes(pkg) {
+			opts, err := annotated.options(base)
+			if err != nil {
+				return nil, err
+			}
+
+			gen.options = opts
+			command := strings.Join(append([]string{"-type=" + annotated.name}, annotated.args...), " ")
+			file, err := gen.generateFile(outputPath(packageDir(pkg), annotated.name), annotated.name, command)
+			if err != nil {
+				return nil, err
+			}
+			files = append(files, file)
+		}
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrNoAnnotatedTypes, strings.Join(patterns, " "))
+	}
+	return files, nil
+}
+
+func (g *generator) generateFile(output, typeName, command string) (File, error) {
+	src, err := g.generate(typeName, command)
+	if err != nil {
+		return File{}, err
+	}
+	data, err := imports.Process(output, src, nil)
+	if err != nil {
+		return File{}, fmt.Errorf("go import failed: %w", err)
+	}
+	return File{Path: output, TypeName: typeName, Source: data}, nil
+}
+
+// args returns the flags setting o, in lexicographical order.
+func (o Options) args() []string {
+	var args []string
+	fs := flag.NewFlagSet("", flag.ContinueOnError)
+	o.RegisterFlags(fs)
+	fs.VisitAll(func(f *flag.Flag) {
+		if f.Value.String() == "true" {
+			args = append(args, "-"+f.Name)
+		}
+	})
+	return args
+}
+
+func loadPackages(dir string, patterns []string) ([]*packages.Package, error) {
+	cfg := &packages.Config{
+		Mode: packages.NeedName | packages.NeedFiles | packages.NeedSyntax |
+			packages.NeedTypes | packages.NeedTypesInfo | packages.NeedImports | packages.NeedDeps,
+		Dir: dir,
+	}
+	pkgs, err := packages.Load(cfg, patterns...)
+	if err != nil {
+		return nil, fmt.Errorf("load packages %s failed: %w", strings.Join(patterns, " "), err)
+	}
+	if len(pkgs) == 0 {
+		return nil, fmt.Errorf("no packages matched: %s", strings.Join(patterns, " "))
+	}
+
+	for _, pkg := range pkgs {
+		// type errors are tolerated so that a stale generated file referring to
+		// removed fields does not prevent its own regeneration.
+		for _, e := range pkg.Errors {
+			if e.Kind != packages.TypeError {
+				return nil, fmt.Errorf("load package %s failed: %s", pkg.PkgPath, e)
+			}
+		}
+		if len(pkg.GoFiles) == 0 {
+			return nil, fmt.Errorf("no buildable go files: %s", pkg.PkgPath)
+		}
+	}
+	return pkgs, nil
+}
+
+func packageDir(pkg *packages.Package) string {
+	return filepath.Dir(pkg.GoFiles[0])
+}
+
+func outputPath(dir, typeName string) string {
+	return filepath.Join(dir, fmt.Sprintf("%s_accessor.go", camelToSnake(baseTypeName(typeName))))
+}
+
+func baseTypeName(typeName string) string {
+	return strings.Split(typeName, "[")[0]
+}
+
+type annotatedType struct {
+	name string
+	args []string
+}
+
+func annotatedTypes(pkg *packages.Package) []annotatedType {
+	var types []annotatedType
+	for _, file := range pkg.Syntax {
+		for _, decl := range file.Decls {
+			gdecl, ok := decl.(*ast.GenDecl)
+			if !ok || gdecl.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range gdecl.Specs {
+				tspec := spec.(*ast.TypeSpec)
+				if _, ok := tspec.Type.(*ast.StructType); !ok {
+					continue
+				}
+				doc := tspec.Doc
+				if doc == nil && !gdecl.Lparen.IsValid() {
+					doc = gdecl.Doc
+				}
+				if args, ok := markerArgs(doc); ok {
+					types = append(types, annotatedType{name: tspec.Name.Name, args: args})
+				}
+			}
+		}
+	}
+	return types
+}
+
+// options returns base overridden by the options following the marker.
+func (a annotatedType) options(base Options) (Options, error) {
+	opts := base
+	fs := flag.NewFlagSet(a.name, flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	opts.RegisterFlags(fs)
+	if err := fs.Parse(a.args); err != nil {
+		return Options{}, fmt.Errorf("parse %s options of type %s failed: %w", MarkerComment, a.name, err)
+	}
+	return opts, nil
+}
+
+func markerArgs(doc *ast.CommentGroup) ([]string, bool) {
+	if doc == nil {
+		return nil, false
+	}
+	for _, comment := range doc.List {
+		rest, ok := strings.CutPrefix(comment.Text, MarkerComment)
+		if !ok || (rest != "" && rest[0] != ' ' && rest[0] != '\t') {
+			continue
+		}
+		return strings.Fields(rest), true
+	}
+	return nil, false
+}
@@ -0,0 +1,244 @@
+package accessor
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var update = flag.Bool("update", false, "update golden files")
+
+// assertGolden compares got with testdata/golden/{name}.golden, rewriting the
+// golden file instead when the -update flag is given.
+func assertGolden(t *testing.T, name string, got []byte) {
+	t.Helper()
+
+	path := filepath.Join("testdata", "golden", name+".golden")
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("error when creating golden dir: %v", err)
+		}
+		if err := os.WriteFile(path, got, 0644); err != nil {
+			t.Fatalf("error when writing golden file: %v", err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("error when reading golden file: %v", err)
+	}
+	assert.Equal(t, string(want), string(got))
+}
+
+func TestGenerate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		golden string
+		config Config
+	}{
+		{
+			name:   "Generic_IndexListExpr",
+			golden: "generic_pair",
+			config: Config{
+				Dir:     "testdata/generic",
+				Types:   []string{"Pair[K, V]"},
+				Options: Options{Setter: true, Builder: true},
+			},
+		},
+		{
+			name:   "Generic_With",
+			golden: "generic_pair_with",
+			config: Config{
+				Dir:     "testdata/generic",
+				Types:   []string{"Pair"},
+				Options: Options{With: true, Equal: true, Clone: true},
+			},
+		},
+		{
+			name:   "Clone_Deep",
+			golden: "clone_node",
+			config: Config{
+				Dir:     "testdata/clone",
+				Types:   []string{"Node"},
+				Options: Options{Clone: true},
+			},
+		},
+		{
+			name:   "JSON_EmbeddedStruct",
+			golden: "embedded_counter",
+			config: Config{
+				Dir:     "testdata/embedded",
+				Types:   []string{"Counter"},
+				Options: Options{JSON: true},
+			},
+		},
+		{
+			name:   "Initialisms",
+			golden: "initialism_user",
+			config: Config{
+				Dir:   "testdata/initialism",
+				Types: []string{"User"},
+			},
+		},
+		{
+			name:   "Visibility_UnexportedTypesSkipped",
+			golden: "visibility_order",
+			config: Config{
+				Dir:     "testdata/visibility",
+				Types:   []string{"Order"},
+				Options: Options{Setter: true, Builder: true},
+			},
+		},
+		{
+			name:   "Equal_SkipFieldIgnored",
+			golden: "visibility_order_equal",
+			config: Config{
+				Dir:     "testdata/visibility",
+				Types:   []string{"Order"},
+				Options: Options{Equal: true},
+			},
+		},
+		{
+			name:   "Command_Header",
+			golden: "initialism_user_command",
+			config: Config{
+				Dir:     "testdata/initialism",
+				Types:   []string{"User"},
+				Command: "-type=User ./testdata/initialism",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tc := tt
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := Generate(tc.config)
+			assert.NoError(t, err)
+			if err == nil {
+				assertGolden(t, tc.golden, got)
+			}
+		})
+	}
+}
+
+func TestGenerateFiles_Annotated(t *testing.T) {
+	t.Parallel()
+
+	files, err := GenerateFiles(Config{Dir: "testdata/annotated"})
+	assert.NoError(t, err)
+
+	var typeNames []string
+	for _, file := range files {
+		typeNames = append(typeNames, file.TypeName)
+		assert.Equal(t, camelToSnake(file.TypeName)+"_accessor.go", filepath.Base(file.Path))
+		assertGolden(t, "annotated_"+camelToSnake(file.TypeName), file.Source)
+	}
+	assert.Equal(t, []string{"Code", "Product"}, typeNames)
+}
+
+func TestGenerateFiles_AnnotatedError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		config Config
+		target error
+	}{
+		{
+			name:   "OutputWithoutTypes",
+			config: Config{Dir: "testdata/annotated", Output: "annotated_accessor.go"},
+		},
+		{
+			name:   "NoAnnotatedTypes",
+			config: Config{Dir: "testdata/invalid"},
+			target: ErrNoAnnotatedTypes,
+		},
+	}
+
+	for _, tt := range tests {
+		tc := tt
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			files, err := GenerateFiles(tc.config)
+			assert.Error(t, err)
+			assert.Empty(t, files)
+			if tc.target != nil {
+				assert.ErrorIs(t, err, tc.target)
+			}
+		})
+	}
+}
+
+func TestGenerate_Error(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		config Config
+	}{
+		{
+			name:   "NotExistType",
+			config: Config{Dir: "testdata/invalid", Types: []string{"Missing"}},
+		},
+		{
+			name:   "UnknownTagOption",
+			config: Config{Dir: "testdata/invalid", Types: []string{"UnknownTag"}},
+		},
+		{
+			name:   "ReadonlyWithSetter",
+			config: Config{Dir: "testdata/invalid", Types: []string{"ReadonlySetter"}},
+		},
+		{
+			name:   "NoPrivateField",
+			config: Config{Dir: "testdata/invalid", Types: []string{"NoPrivateField"}},
+		},
+		{
+			name:   "DuplicateAccessorName",
+			config: Config{Dir: "testdata/invalid", Types: []string{"DuplicateName"}},
+		},
+		{
+			name:   "AccessorNameOfGeneratedMethod",
+			config: Config{Dir: "testdata/invalid", Types: []string{"EqualName"}, Options: Options{Equal: true}},
+		},
+		{
+			name:   "TextWithTwoFields",
+			config: Config{Dir: "testdata/invalid", Types: []string{"TwoFields"}, Options: Options{Text: true}},
+		},
+		{
+			name:   "ValidatedSettersWithoutValidate",
+			config: Config{Dir: "testdata/invalid", Types: []string{"TwoFields"}, Options: Options{Setter: true, ValidatedSetters: true}},
+		},
+		{
+			name:   "CloneRecursiveType",
+			config: Config{Dir: "testdata/clone", Types: []string{"Cycle"}, Options: Options{Clone: true}},
+		},
+		{
+			name:   "JSONUnexportedStructField",
+			config: Config{Dir: "testdata/embedded", Types: []string{"Opaque"}, Options: Options{JSON: true}},
+		},
+		{
+			name:   "MultipleTypes",
+			config: Config{Dir: "testdata/invalid", Types: []string{"TwoFields", "UnknownTag"}},
+		},
+		{
+			name:   "NoPackage",
+			config: Config{Dir: "testdata/missing", Types: []string{"TwoFields"}},
+		},
+	}
+
+	for _, tt := range tests {
+		tc := tt
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			_, err := Generate(tc.config)
+			assert.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,960 @@
+package accessor
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/format"
+	"go/token"
+	"go/types"
+	"reflect"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+type generator struct {
+	options Options
+	// base holds the options of the run, and explicit the types given by
+	// Config.Types, to predict the methods generated for other types.
+	base     Options
+	explicit map[string]bool
+	buf      bytes.Buffer
+	pkg      *packages.Package
+	imports  map[*types.Package]string
+}
+
+func (g *generator) Printf(format string, args ...interface{}) {
+	fmt.Fprintf(&g.buf, format, args...)
+}
+
+// optionsOf returns the options the named type is generated with in this run,
+// either because it is given by Config.Types or annotated with MarkerComment.
+func (g *generator) optionsOf(named *types.Named) (Options, bool) {
+	obj := named.Origin().Obj()
+	if obj.Pkg() == nil {
+		return Options{}, false
+	}
+	if obj.Pkg() == g.pkg.Types && g.explicit[obj.Name()] {
+		return g.base, true
+	}
+
+	var pkg *packages.Package
+	packages.Visit([]*packages.Package{g.pkg}, func(p *packages.Package) bool {
+		if p.PkgPath == obj.Pkg().Path() {
+			pkg = p
+		}
+		return pkg == nil
+	}, nil)
+	if pkg == nil {
+		return Options{}, false
+	}
+	for _, annotated := range annotatedTypes(pkg) {
+		if annotated.name == obj.Name() {
+			opts, err := annotated.options(g.base)
+			return opts, err == nil
+		}
+	}
+	return Options{}, false
+}
+
+func (g *generator) generate(typeName, command string) ([]byte, error) {
+	baseTypeName := baseTypeName(typeName)
+
+	var named *types.Named
+	var stype *types.Struct
+	if obj, ok := g.pkg.Types.Scope().Lookup(baseTypeName).(*types.TypeName); ok && !obj.IsAlias() {
+		named, _ = obj.Type().(*types.Named)
+	}
+	if named != nil {
+		stype, _ = named.Underlying().(*types.Struct)
+	}
+	if stype == nil {
+		return nil, fmt.Errorf("not exist type %s", typeName)
+	}
+
+	g.imports = make(map[*types.Package]string)
+	g.buf.Reset()
+	if err := g.build(named, stype); err != nil {
+		return nil, fmt.Errorf("type %s: %w", typeName, err)
+	}
+
+	var src bytes.Buffer
+	fmt.Fprintf(&src, "// Code generated by \"accessor %s\"; DO NOT EDIT.\n\n", command)
+	fmt.Fprintf(&src, "package %s\n", g.pkg.Name)
+	g.writeImports(&src)
+	src.Write(g.buf.Bytes())
+	g.buf.Reset()
+
+	formatted, err := format.Source(src.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("format source failed: %w", err)
+	}
+	return formatted, nil
+}
+
+// qualifier names the packages referenced by generated code, registering an
+// import for each one and renaming it when its name is already taken.
+func (g *generator) qualifier(p *types.Package) string {
+	if p == g.pkg.Types {
+		return ""
+	}
+	if name, ok := g.imports[p]; ok {
+		return name
+	}
+
+	name := p.Name()
+	for i := 2; g.importNameUsed(name); i++ {
+		name = fmt.Sprintf("%s%d", p.Name(), i)
+	}
+	g.imports[p] = name
+	return name
+}
+
+func (g *generator) importNameUsed(name string) bool {
+	for _, used := range g.imports {
+		if used == name {
+			return true
+		}
+	}
+	return false
+}
+
+func (g *generator) writeImports(w *bytes.Buffer) {
+	if len(g.imports) == 0 {
+		return
+	}
+	pkgs := make([]*types.Package, 0, len(g.imports))
+	for p := range g.imports {
+		pkgs = append(pkgs, p)
+	}
+	sort.Slice(pkgs, func(i, j int) bool { return pkgs[i].Path() < pkgs[j].Path() })
+
+	fmt.Fprintln(w, "\nimport (")
+	for _, p := range pkgs {
+		name := g.imports[p]
+		if name == p.Name() {
+			fmt.Fprintf(w, "\t%q\n", p.Path())
+		} else {
+			fmt.Fprintf(w, "\t%s %q\n", name, p.Path())
+		}
+	}
+	fmt.Fprintln(w, ")")
+}
+
+// hasMethod reports whether the named type has a method with the given name,
+// regardless of the receiver kind.
+func (g *generator) hasMethod(named *types.Named, methodName string) bool {
+	obj, _, _ := types.LookupFieldOrMethod(named, true, g.pkg.Types, methodName)
+	_, ok := obj.(*types.Func)
+	return ok
+}
+
+const (
+	getterFormat = `// %[2]s return %[3]s value
+func (t %[1]s) %[2]s() %[4]s {
+	return t.%[3]s
+}
+`
+	setterFormat = `// Set%[2]s set v to %[3]s
+func (t *%[1]s) Set%[2]s(v %[4]s) {
+	t.%[3]s = v
+}
+`
+	validatedSetterFormat = `// Set%[2]s set v to %[3]s if it passes validate method
+func (t *%[1]s) Set%[2]s(v %[4]s) error {
+	old := t.%[3]s
+	t.%[3]s = v
+	if err := t.validate(); err != nil {
+		t.%[3]s = old
+		return err
+	}
+	return nil
+}
+`
+	withFormat = `// With%[2]s return copy with v set to %[3]s
+func (t %[1]s) With%[2]s(v %[4]s) (%[1]s, error) {
+	t.%[3]s = v
+	return t, nil
+}
+`
+	withValidateFormat = `// With%[2]s return copy with v set to %[3]s validated by validate method
+func (t %[1]s) With%[2]s(v %[4]s) (%[1]s, error) {
+	t.%[3]s = v
+	if err := t.validate(); err != nil {
+		return %[1]s{}, err
+	}
+	return t, nil
+}
+`
+	equalFormat = `// Equal return whether t and other are equal
+func (t %[1]s) Equal(other %[1]s) bool {
+	return %[2]s
+}
+`
+	cloneFormat = `// Clone return deep copy of t
+func (t %[1]s) Clone() %[1]s {
+	c := t
+%[2]s	return c
+}
+`
+	marshalJSONFormat = `// MarshalJSON implement json.Marshaler
+func (t %[1]s) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+%[2]s	}{
+%[3]s	})
+}
+`
+	unmarshalJSONFormat = `// UnmarshalJSON implement json.Unmarshaler
+func (t *%[1]s) UnmarshalJSON(data []byte) error {
+	var v struct {
+%[2]s	}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	var u %[1]s
+%[3]s%[4]s	*t = u
+	return nil
+}
+`
+	textFormat = `// MarshalText implement encoding.TextMarshaler
+func (t %[1]s) MarshalText() ([]byte, error) {
+	%[2]s
+}
+
+// UnmarshalText implement encoding.TextUnmarshaler
+func (t *%[1]s) UnmarshalText(text []byte) error {
+	%[3]s
+%[4]s	*t = u
+	return nil
+}
+`
+	parseIntFormat = `v, err := strconv.%[4]s(string(text), 10, %[5]d)
+	if err != nil {
+		return err
+	}
+	u := %[1]s{%[2]s: %[3]s(v)}`
+	validateCopyStmt = `	if err := u.validate(); err != nil {
+		return err
+	}
+`
+	builderFormat = `// %[1]sBuilder build %[1]s value
+type %[1]sBuilder%[2]s struct {
+	t %[1]s%[3]s
+}
+
+// New%[1]sBuilder return new %[1]sBuilder
+func New%[1]sBuilder%[2]s() *%[1]sBuilder%[3]s {
+	return &%[1]sBuilder%[3]s{}
+}
+`
+	builderWithFormat = `// With%[2]s set v to %[3]s
+func (b *%[1]s) With%[2]s(v %[4]s) *%[1]s {
+	b.t.%[3]s = v
+	return b
+}
+`
+	buildFormat = `// Build return %[1]s value
+func (b *%[2]s) Build() (*%[1]s, error) {
+	t := b.t
+	return &t, nil
+}
+`
+	buildWithValidateFormat = `// Build return %[1]s value validated by validate method
+func (b *%[2]s) Build() (*%[1]s, error) {
+	t := b.t
+	if err := t.validate(); err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+`
+)
+
+// field is a struct field reachable from the generated type with a plain
+// selector, either declared on the type itself or promoted from an embedded struct.
+type field struct {
+	v   *types.Var
+	tag string
+}
+
+// fields returns the unexported fields of typ, including those promoted from
+// embedded structs of the same package, in declaration order.
+func (g *generator) fields(named *types.Named, typ *types.Struct) []field {
+	var fields []field
+	for i := 0; i < typ.NumFields(); i++ {
+		v := typ.Field(i)
+		if v.Embedded() {
+			// embedded pointers are not followed since the promoted fields of a
+			// nil pointer cannot be read or written.
+			if en, ok := v.Type().(*types.Named); ok && en.Obj().Pkg() == g.pkg.Types {
+				if est, ok := en.Underlying().(*types.Struct); ok {
+					for _, f := range g.fields(en, est) {
+						if g.selects(named, f.v) {
+							fields = append(fields, f)
+						}
+					}
+				}
+			}
+		}
+		if v.Exported() {
+			continue
+		}
+		fields = append(fields, field{v: v, tag: typ.Tag(i)})
+	}
+	return fields
+}
+
+// selects reports whether the selector t.name of the named type denotes v,
+// i.e. v is neither shadowed nor ambiguous.
+func (g *generator) selects(named *types.Named, v *types.Var) bool {
+	obj, _, _ := types.LookupFieldOrMethod(named, false, g.pkg.Types, v.Name())
+	return obj == v
+}
+
+func (g *generator) build(named *types.Named, typ *types.Struct) error {
+	if typ.NumFields() == 0 {
+		return errors.New("struct has no field")
+	}
+	typeName := named.Obj().Name()
+	hasValidate := g.hasMethod(named, "validate")
+	if g.options.ValidatedSetters && !hasValidate {
+		return errors.New("-validated-setters requires a validate method")
+	}
+	exist := false
+
+	typeGenericSig := ""
+	typeGenericArgs := ""
+	if tparams := named.TypeParams(); tparams.Len() > 0 {
+		var params, args []string
+		for i := 0; i < tparams.Len(); i++ {
+			tp := tparams.At(i)
+			params = append(params, fmt.Sprintf("%s %s", tp.Obj().Name(), types.TypeString(tp.Constraint(), g.qualifier)))
+			args = append(args, tp.Obj().Name())
+		}
+		typeGenericSig = "[" + strings.Join(params, ", ") + "]"
+		typeGenericArgs = "[" + strings.Join(args, ", ") + "]"
+	}
+	recvType := fmt.Sprintf("%s%s", typeName, typeGenericArgs)
+	builderType := fmt.Sprintf("%sBuilder%s", typeName, typeGenericArgs)
+
+	var builderBuf bytes.Buffer
+	exportNames := make(map[string]string)
+	generated := g.options.methods()
+	for _, field := range g.fields(named, typ) {
+		name := field.v.Name()
+		tag, err := parseFieldTag(name, field.tag)
+		if err != nil {
+			return err
+		}
+		if tag.skip {
+			continue
+		}
+		typStr, err := g.typeString(name, field.v.Type())
+		if err != nil {
+			return err
+		}
+		if typStr == "" {
+			continue
+		}
+
+		exportName := upperNameSmart(name)
+		if tag.getter != "" {
+			exportName = tag.getter
+		}
+		if other, ok := exportNames[exportName]; ok {
+			return fmt.Errorf("fields %s and %s have the same accessor name %s", other, name, exportName)
+		}
+		if flag, ok := generated[exportName]; ok {
+			return fmt.Errorf("accessor name %s of field %s collides with the method generated by -%s", exportName, name, flag)
+		}
+		exportNames[exportName] = name
+
+		g.Printf(getterFormat, recvType, exportName, name, typStr)
+		if (g.options.Setter || tag.setter) && !tag.readonly {
+			if g.options.ValidatedSetters {
+				g.Printf(validatedSetterFormat, recvType, exportName, name, typStr)
+			} else {
+				g.Printf(setterFormat, recvType, exportName, name, typStr)
+			}
+		}
+		if (g.options.With || tag.with) && !tag.readonly {
+			if hasValidate {
+				g.Printf(withValidateFormat, recvType, exportName, name, typStr)
+			} else {
+				g.Printf(withFormat, recvType, exportName, name, typStr)
+			}
+		}
+		if g.options.Builder {
+			fmt.Fprintf(&builderBuf, builderWithFormat, builderType, exportName, name, typStr)
+		}
+		exist = true
+	}
+
+	if !exist {
+		return errors.New("struct doesn't have private fields to generate accessors")
+	}
+
+	if g.options.Equal {
+		if err := g.buildEqual(recvType, typ); err != nil {
+			return err
+		}
+	}
+	if g.options.Clone {
+		if err := g.buildClone(recvType, typ); err != nil {
+			return err
+		}
+	}
+	if g.options.JSON {
+		if err := g.buildJSON(recvType, named, typ, hasValidate); err != nil {
+			return err
+		}
+	}
+	if g.options.Text {
+		if err := g.buildText(recvType, typ, hasValidate); err != nil {
+			return err
+		}
+	}
+
+	if g.options.Builder {
+		g.Printf(builderFormat, typeName, typeGenericSig, typeGenericArgs)
+		g.buf.Write(builderBuf.Bytes())
+		if hasValidate {
+			g.Printf(buildWithValidateFormat, recvType, builderType)
+		} else {
+			g.Printf(buildFormat, recvType, builderType)
+		}
+	}
+	return nil
+}
+
+// buildEqual generates an Equal method comparing every field of typ, except
+// those tagged with skip as they hold no value of their own. Fields are
+// compared with their own Equal method when present, with == when comparable,
+// and with reflect.DeepEqual otherwise. Pointers are compared by the values
+// they point to, so that a Clone equals its original.
+func (g *generator) buildEqual(recvType string, typ *types.Struct) error {
+	var exprs []string
+	for i := 0; i < typ.NumFields(); i++ {
+		v := typ.Field(i)
+		tag, err := parseFieldTag(v.Name(), typ.Tag(i))
+		if err != nil {
+			return err
+		}
+		if tag.skip {
+			continue
+		}
+
+		switch {
+		case g.hasEqualMethod(v.Type()):
+			exprs = append(exprs, fmt.Sprintf("t.%[1]s.Equal(other.%[1]s)", v.Name()))
+		case types.Comparable(v.Type()) && !isPointer(v.Type()):
+			exprs = append(exprs, fmt.Sprintf("t.%[1]s == other.%[1]s", v.Name()))
+		default:
+			exprs = append(exprs, fmt.Sprintf("reflect.DeepEqual(t.%[1]s, other.%[1]s)", v.Name()))
+		}
+	}
+	if len(exprs) == 0 {
+		exprs = append(exprs, "true")
+	}
+	g.Printf(equalFormat, recvType, strings.Join(exprs, " &&\n\t\t"))
+	return nil
+}
+
+// methods returns the names of the methods generated for a type as a whole,
+// which no accessor may take, mapped to the flag generating them.
+func (o Options) methods() map[string]string {
+	methods := make(map[string]string)
+	if o.Equal {
+		methods["Equal"] = "equal"
+	}
+	if o.Clone {
+		methods["Clone"] = "clone"
+	}
+	if o.JSON {
+		methods["MarshalJSON"] = "json"
+		methods["UnmarshalJSON"] = "json"
+	}
+	if o.Text {
+		methods["MarshalText"] = "text"
+		methods["UnmarshalText"] = "text"
+	}
+	return methods
+}
+
+// buildClone generates a Clone method copying typ deeply: fields with their
+// own Clone method are cloned, and pointers, slices, maps, arrays and structs
+// are copied recursively. Other values, like interfaces, channels, functions
+// and the fields of structs that cannot be accessed from the package, are
+// copied by assignment.
+func (g *generator) buildClone(recvType string, typ *types.Struct) error {
+	var stmts strings.Builder
+	for i := 0; i < typ.NumFields(); i++ {
+		v := typ.Field(i)
+		if err := g.cloneStmt(&stmts, "c."+v.Name(), "t."+v.Name(), v.Type(), 0, nil); err != nil {
+			return fmt.Errorf("field %s: %w", v.Name(), err)
+		}
+	}
+	g.Printf(cloneFormat, recvType, stmts.String())
+	return nil
+}
+
+// cloneStmt writes the statements turning dst, which holds a copy of src made
+// by assignment, into a deep copy of src. depth numbers the variables of
+// nested loops, and expanding holds the structs being copied, to detect
+// recursive types.
+func (g *generator) cloneStmt(w *strings.Builder, dst, src string, typ types.Type, depth int, expanding []types.Type) error {
+	if !g.needsClone(typ) {
+		return nil
+	}
+	if g.hasCloneMethod(typ) {
+		fmt.Fprintf(w, "%s = %s.Clone()\n", dst, operand(src))
+		return nil
+	}
+
+	i, k, v := cloneVar("i", depth), cloneVar("k", depth), cloneVar("v", depth)
+	switch t := typ.Underlying().(type) {
+	case *types.Pointer:
+		fmt.Fprintf(w, "if %s != nil {\n", src)
+		if g.hasCloneMethod(t.Elem()) {
+			fmt.Fprintf(w, "%s := %s.Clone()\n", v, src)
+		} else {
+			fmt.Fprintf(w, "%s := *%s\n", v, src)
+			if err := g.cloneStmt(w, v, "*"+src, t.Elem(), depth+1, expanding); err != nil {
+				return err
+			}
+		}
+		fmt.Fprintf(w, "%s = &%s\n}\n", dst, v)
+	case *types.Slice:
+		fmt.Fprintf(w, "%s = slices.Clone(%s)\n", dst, src)
+		if g.needsClone(t.Elem()) {
+			fmt.Fprintf(w, "for %s, %s := range %s {\n", i, v, src)
+			if err := g.cloneStmt(w, dst+"["+i+"]", v, t.Elem(), depth+1, expanding); err != nil {
+				return err
+			}
+			fmt.Fprintf(w, "}\n")
+		}
+	case *types.Map:
+		fmt.Fprintf(w, "%s = maps.Clone(%s)\n", dst, src)
+		if g.needsClone(t.Elem()) {
+			// map elements are not addressable, so they are copied to a variable.
+			e := cloneVar("e", depth)
+			fmt.Fprintf(w, "for %s, %s := range %s {\n%s := %s\n", k, v, src, e, v)
+			if err := g.cloneStmt(w, e, v, t.Elem(), depth+1, expanding); err != nil {
+				return err
+			}
+			fmt.Fprintf(w, "%s[%s] = %s\n}\n", dst, k, e)
+		}
+	case *types.Array:
+		fmt.Fprintf(w, "for %s, %s := range %s {\n", i, v, src)
+		if err := g.cloneStmt(w, dst+"["+i+"]", v, t.Elem(), depth+1, expanding); err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "}\n")
+	case *types.Struct:
+		for _, e := range expanding {
+			if types.Identical(e, typ) {
+				return fmt.Errorf("recursive type %s requires a Clone method", types.TypeString(typ, g.qualifier))
+			}
+		}
+		expanding = append(expanding, typ)
+		for j := 0; j < t.NumFields(); j++ {
+			f := t.Field(j)
+			if !g.accessible(f) {
+				continue
+			}
+			if err := g.cloneStmt(w, dst+"."+f.Name(), operand(src)+"."+f.Name(), f.Type(), depth, expanding); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// needsClone reports whether a copy of typ by assignment shares memory that
+// Clone can copy.
+func (g *generator) needsClone(typ types.Type) bool {
+	if g.hasCloneMethod(typ) {
+		return true
+	}
+	switch t := typ.Underlying().(type) {
+	case *types.Pointer, *types.Slice, *types.Map:
+		return true
+	case *types.Array:
+		return g.needsClone(t.Elem())
+	case *types.Struct:
+		for i := 0; i < t.NumFields(); i++ {
+			if f := t.Field(i); g.accessible(f) && g.needsClone(f.Type()) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// accessible reports whether the generated code can select the field v.
+func (g *generator) accessible(v *types.Var) bool {
+	return v.Exported() || v.Pkg() == g.pkg.Types
+}
+
+// operand parenthesizes a dereference x to select from it.
+func operand(x string) string {
+	if strings.HasPrefix(x, "*") {
+		return "(" + x + ")"
+	}
+	return x
+}
+
+// cloneVar names a variable of the generated Clone method at a loop depth.
+func cloneVar(name string, depth int) string {
+	if depth == 0 {
+		return name
+	}
+	return fmt.Sprintf("%s%d", name, depth+1)
+}
+
+// buildJSON generates MarshalJSON and UnmarshalJSON methods encoding the fields
+// of typ, except those tagged with skip, as an object with snake case keys.
+// The fields of embedded structs of the same package are encoded as fields of
+// typ. Fields with an IsUndefined method, like undefined.Undefined, are omitted
+// while undefined. UnmarshalJSON keeps t unchanged unless validate method passes.
+func (g *generator) buildJSON(recvType string, named *types.Named, typ *types.Struct, hasValidate bool) error {
+	fields, err := g.jsonFields(named, typ)
+	if err != nil {
+		return err
+	}
+
+	var decls, marshal, unmarshal strings.Builder
+	keys := make(map[string]string)
+	for _, f := range fields {
+		name := f.v.Name()
+		if !g.marshalsJSON(f.v.Type(), nil) {
+			return fmt.Errorf("field %s of type %s cannot be marshalled to JSON", name, types.TypeString(f.v.Type(), g.qualifier))
+		}
+		key := camelToSnake(name)
+		if other, ok := keys[key]; ok {
+			return fmt.Errorf("fields %s and %s have the same JSON key %s", other, name, key)
+		}
+		keys[key] = name
+		if g.lookupMethod(f.v.Type(), "IsUndefined") != nil {
+			key += ",omitzero"
+		}
+		exportName := upperNameSmart(name)
+		fmt.Fprintf(&decls, "\t\t%s %s `json:%q`\n", exportName, types.TypeString(f.v.Type(), g.qualifier), key)
+		fmt.Fprintf(&marshal, "\t\t%s: t.%s,\n", exportName, name)
+		fmt.Fprintf(&unmarshal, "\tu.%s = v.%s\n", name, exportName)
+	}
+
+	g.Printf(marshalJSONFormat, recvType, decls.String(), marshal.String())
+	validate := ""
+	if hasValidate {
+		validate = validateCopyStmt
+	}
+	g.Printf(unmarshalJSONFormat, recvType, decls.String(), unmarshal.String(), validate)
+	return nil
+}
+
+// jsonFields returns the fields of typ encoded by buildJSON: its fields that
+// are not tagged with skip, with embedded structs of the same package replaced
+// by their promoted fields.
+func (g *generator) jsonFields(named *types.Named, typ *types.Struct) ([]field, error) {
+	var fields []field
+	for i := 0; i < typ.NumFields(); i++ {
+		v := typ.Field(i)
+		tag, err := parseFieldTag(v.Name(), typ.Tag(i))
+		if err != nil {
+			return nil, err
+		}
+		if tag.skip {
+			continue
+		}
+
+		if v.Embedded() {
+			if en, ok := v.Type().(*types.Named); ok && en.Obj().Pkg() == g.pkg.Types {
+				if est, ok := en.Underlying().(*types.Struct); ok {
+					promoted, err := g.jsonFields(en, est)
+					if err != nil {
+						return nil, err
+					}
+					for _, f := range promoted {
+						if g.selects(named, f.v) {
+							fields = append(fields, f)
+						}
+					}
+					continue
+				}
+			}
+		}
+		fields = append(fields, field{v: v, tag: typ.Tag(i)})
+	}
+	return fields, nil
+}
+
+// marshalsJSON reports whether encoding/json round-trips values of typ, which
+// does not hold for structs with unexported fields and no JSON or text
+// marshalling methods. seen holds the named types being checked.
+func (g *generator) marshalsJSON(typ types.Type, seen []*types.Named) bool {
+	for _, method := range []string{"MarshalJSON", "MarshalText"} {
+		if g.lookupMethod(typ, method) != nil || g.lookupMethod(types.NewPointer(typ), method) != nil {
+			return true
+		}
+	}
+	if named, ok := types.Unalias(typ).(*types.Named); ok {
+		if opts, ok := g.optionsOf(named); ok && (opts.JSON || opts.Text) {
+			return true
+		}
+		for _, s := range seen {
+			if s == named {
+				return true
+			}
+		}
+		seen = append(seen, named)
+	}
+
+	switch t := typ.Underlying().(type) {
+	case *types.Pointer:
+		return g.marshalsJSON(t.Elem(), seen)
+	case *types.Slice:
+		return g.marshalsJSON(t.Elem(), seen)
+	case *types.Array:
+		return g.marshalsJSON(t.Elem(), seen)
+	case *types.Map:
+		return g.marshalsJSON(t.Elem(), seen)
+	case *types.Struct:
+		for i := 0; i < t.NumFields(); i++ {
+			if f := t.Field(i); (!f.Exported() && !f.Embedded()) || !g.marshalsJSON(f.Type(), seen) {
+				return false
+			}
+		}
+		return true
+	case *types.Chan, *types.Signature:
+		return false
+	}
+	return true
+}
+
+// buildText generates MarshalText and UnmarshalText methods for a struct
+// holding a single string, integer or encoding.TextMarshaler field.
+func (g *generator) buildText(recvType string, typ *types.Struct, hasValidate bool) error {
+	if typ.NumFields() != 1 {
+		return errors.New("-text requires a single field")
+	}
+	v := typ.Field(0)
+
+	var marshal, unmarshal string
+	switch {
+	case g.lookupMethod(v.Type(), "MarshalText") != nil:
+		marshal = fmt.Sprintf("return t.%s.MarshalText()", v.Name())
+		unmarshal = fmt.Sprintf(`var u %[1]s
+	if err := u.%[2]s.UnmarshalText(text); err != nil {
+		return err
+	}`, recvType, v.Name())
+	default:
+		basic, ok := v.Type().Underlying().(*types.Basic)
+		if !ok {
+			return fmt.Errorf("-text does not support field %s of type %s", v.Name(), v.Type())
+		}
+		typStr := types.TypeString(v.Type(), g.qualifier)
+		switch {
+		case basic.Info()&types.IsString != 0:
+			marshal = fmt.Sprintf("return []byte(t.%s), nil", v.Name())
+			unmarshal = fmt.Sprintf("u := %s{%s: %s(text)}", recvType, v.Name(), typStr)
+		case basic.Info()&types.IsUnsigned != 0:
+			marshal = fmt.Sprintf("return strconv.AppendUint(nil, uint64(t.%s), 10), nil", v.Name())
+			unmarshal = fmt.Sprintf(parseIntFormat, recvType, v.Name(), typStr, "ParseUint", bitSize(basic))
+		case basic.Info()&types.IsInteger != 0:
+			marshal = fmt.Sprintf("return strconv.AppendInt(nil, int64(t.%s), 10), nil", v.Name())
+			unmarshal = fmt.Sprintf(parseIntFormat, recvType, v.Name(), typStr, "ParseInt", bitSize(basic))
+		default:
+			return fmt.Errorf("-text does not support field %s of type %s", v.Name(), v.Type())
+		}
+	}
+
+	validate := ""
+	if hasValidate {
+		validate = validateCopyStmt
+	}
+	g.Printf(textFormat, recvType, marshal, unmarshal, validate)
+	return nil
+}
+
+// bitSize returns the bit size of an integer type for strconv, 0 meaning int.
+func bitSize(basic *types.Basic) int {
+	switch basic.Kind() {
+	case types.Int8, types.Uint8:
+		return 8
+	case types.Int16, types.Uint16:
+		return 16
+	case types.Int32, types.Uint32:
+		return 32
+	case types.Int64, types.Uint64:
+		return 64
+	}
+	return 0
+}
+
+func isPointer(typ types.Type) bool {
+	_, ok := typ.Underlying().(*types.Pointer)
+	return ok
+}
+
+// hasEqualMethod reports whether typ has, or is generated in this run with,
+// a method Equal(typ) bool.
+func (g *generator) hasEqualMethod(typ types.Type) bool {
+	if sig := g.lookupMethod(typ, "Equal"); sig != nil {
+		return sig.Params().Len() == 1 && types.Identical(sig.Params().At(0).Type(), typ) &&
+			sig.Results().Len() == 1 && types.Identical(sig.Results().At(0).Type(), types.Typ[types.Bool])
+	}
+	if named, ok := types.Unalias(typ).(*types.Named); ok {
+		opts, ok := g.optionsOf(named)
+		return ok && opts.Equal
+	}
+	return false
+}
+
+// hasCloneMethod reports whether typ has, or is generated in this run with,
+// a method Clone() typ.
+func (g *generator) hasCloneMethod(typ types.Type) bool {
+	if sig := g.lookupMethod(typ, "Clone"); sig != nil {
+		return sig.Params().Len() == 0 &&
+			sig.Results().Len() == 1 && types.Identical(sig.Results().At(0).Type(), typ)
+	}
+	if named, ok := types.Unalias(typ).(*types.Named); ok {
+		opts, ok := g.optionsOf(named)
+		return ok && opts.Clone
+	}
+	return false
+}
+
+func (g *generator) lookupMethod(typ types.Type, name string) *types.Signature {
+	obj, _, _ := types.LookupFieldOrMethod(typ, false, g.pkg.Types, name)
+	if fn, ok := obj.(*types.Func); ok {
+		return fn.Type().(*types.Signature)
+	}
+	return nil
+}
+
+// fieldTag holds the per-field options of the accessor struct tag, e.g.
+// `accessor:"getter=FullName,setter"`.
+//
+//	skip      no accessor is generated for the field, and Equal and JSON methods ignore it
+//	getter=X  use X as the exported name of the getter, setter, With and builder method
+//	setter    generate a setter even if -setter is not given
+//	with      generate a With method even if -with is not given
+//	readonly  never generate a setter or With method, even if requested by flag
+type fieldTag struct {
+	skip     bool
+	getter   string
+	setter   bool
+	with     bool
+	readonly bool
+}
+
+const fieldTagKey = "accessor"
+
+func parseFieldTag(fieldName, raw string) (fieldTag, error) {
+	var tag fieldTag
+	value, ok := reflect.StructTag(raw).Lookup(fieldTagKey)
+	if !ok {
+		return tag, nil
+	}
+
+	for _, item := range strings.Split(value, ",") {
+		key, val, _ := strings.Cut(strings.TrimSpace(item), "=")
+		switch key {
+		case "skip":
+			tag.skip = true
+		case "getter":
+			if !token.IsIdentifier(val) || !token.IsExported(val) {
+				return tag, fmt.Errorf("invalid getter name %q of field %s", val, fieldName)
+			}
+			tag.getter = val
+		case "setter":
+			tag.setter = true
+		case "with":
+			tag.with = true
+		case "readonly":
+			tag.readonly = true
+		case "":
+		default:
+			return tag, fmt.Errorf("unknown %s tag option %q of field %s", fieldTagKey, key, fieldName)
+		}
+	}
+	if (tag.setter || tag.with) && tag.readonly {
+		return tag, fmt.Errorf("setter and with are exclusive with readonly for field %s", fieldName)
+	}
+	return tag, nil
+}
+
+// typeString returns the source representation of typ as seen from the
+// generated file, or "" when typ cannot appear in an exported method signature.
+func (g *generator) typeString(fieldName string, typ types.Type) (string, error) {
+	if strings.Contains(types.TypeString(typ, nil), "invalid type") {
+		return "", fmt.Errorf("invalid type of field %s", fieldName)
+	}
+	if !g.isVisible(typ) {
+		return "", nil
+	}
+	return types.TypeString(typ, g.qualifier), nil
+}
+
+func (g *generator) isVisible(typ types.Type) bool {
+	switch t := typ.(type) {
+	case *types.Basic:
+		return t.Kind() != types.Invalid
+	case *types.TypeParam:
+		return true
+	case *types.Alias:
+		return isExportedObj(t.Obj()) && g.areVisible(t.TypeArgs())
+	case *types.Named:
+		return isExportedObj(t.Obj()) && g.areVisible(t.TypeArgs())
+	case *types.Pointer:
+		return g.isVisible(t.Elem())
+	case *types.Slice:
+		return g.isVisible(t.Elem())
+	case *types.Array:
+		return g.isVisible(t.Elem())
+	case *types.Chan:
+		return g.isVisible(t.Elem())
+	case *types.Map:
+		return g.isVisible(t.Key()) && g.isVisible(t.Elem())
+	case *types.Signature:
+		return g.isVisible(t.Params()) && g.isVisible(t.Results())
+	case *types.Tuple:
+		for i := 0; i < t.Len(); i++ {
+			if !g.isVisible(t.At(i).Type()) {
+				return false
+			}
+		}
+		return true
+	case *types.Interface:
+		for i := 0; i < t.NumEmbeddeds(); i++ {
+			if !g.isVisible(t.EmbeddedType(i)) {
+				return false
+			}
+		}
+		for i := 0; i < t.NumExplicitMethods(); i++ {
+			if !g.isVisible(t.ExplicitMethod(i).Type()) {
+				return false
+			}
+		}
+		return true
+	}
+	// anonymous structs and any other type literal are not supported.
+	return false
+}
+
+func (g *generator) areVisible(list *types.TypeList) bool {
+	for i := 0; i < list.Len(); i++ {
+		if !g.isVisible(list.At(i)) {
+			return false
+		}
+	}
+	return true
+}
+
+// isExportedObj reports whether obj is exported or predeclared, like error.
+func isExportedObj(obj *types.TypeName) bool {
+	return obj.Pkg() == nil || obj.Exported()
+}
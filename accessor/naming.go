@@ -0,0 +1,53 @@
+package accessor
+
+import (
+	"regexp"
+	"strings"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/language"
+)
+
+// -----------------------
+// smart upperName section
+// -----------------------
+
+var initialismsList = []string{
+	"ACL", "API", "ASCII", "CPU", "CSS", "DNS", "EOF", "GUID", "HTML", "HTTP", "HTTPS", "ID", "IP", "JSON",
+	"QPS", "RAM", "RPC", "SLA", "SMTP", "SQL", "SSH", "TCP", "TLS", "TTL", "UDP", "UI", "GID", "UID", "ULID",
+	"UUID", "URI", "URL", "UTF8", "VM", "XML", "XMPP", "XSRF", "XSS", "SIP", "RTP", "AMQP", "DB", "TS",
+}
+var initialismsMap = makeInitialismsMap(initialismsList)
+
+func makeInitialismsMap(list []string) map[string]string {
+	m := make(map[string]string, len(list))
+	for _, s := range list {
+		m[strings.ToLower(s)] = s
+	}
+	return m
+}
+
+var camelCaseMatchParts = regexp.MustCompile(`[A-Z]+[a-z0-9]*|[a-z0-9]+`)
+
+func upperNameSmart(s string) string {
+	parts := camelCaseMatchParts.FindAllString(s, -1)
+	for i, p := range parts {
+		lower := strings.ToLower(p)
+		if v, ok := initialismsMap[lower]; ok {
+			parts[i] = v
+		} else {
+			parts[i] = cases.Title(language.English).String(p)
+		}
+	}
+	return strings.Join(parts, "")
+}
+
+// snake case converter
+var matchFirstCap = regexp.MustCompile("(.)([A-Z][a-z]+)")
+var matchAllCap = regexp.MustCompile("([a-z0-9])([A-Z])")
+
+func camelToSnake(s string) string {
+	snake := matchFirstCap.ReplaceAllString(s, "${1}_${2}")
+	snake = matchAllCap.ReplaceAllString(snake, "${1}_${2}")
+	return strings.ToLower(snake)
+}
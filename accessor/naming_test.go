@@ -0,0 +1,57 @@
+package accessor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUpperNameSmart(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{name: "Simple", in: "name", want: "Name"},
+		{name: "CamelCase", in: "customerName", want: "CustomerName"},
+		{name: "Initialism", in: "id", want: "ID"},
+		{name: "TrailingInitialism", in: "userID", want: "UserID"},
+		{name: "LowerInitialism", in: "userId", want: "UserID"},
+		{name: "MultipleInitialisms", in: "htmlURL", want: "HTMLURL"},
+		{name: "LeadingInitialism", in: "apiKey", want: "APIKey"},
+	}
+
+	for _, tt := range tests {
+		tc := tt
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tc.want, upperNameSmart(tc.in))
+		})
+	}
+}
+
+func TestCamelToSnake(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{name: "Simple", in: "Customer", want: "customer"},
+		{name: "CamelCase", in: "ServiceType", want: "service_type"},
+		{name: "LowerCamelCase", in: "customerName", want: "customer_name"},
+		{name: "Initialism", in: "HTMLURL", want: "htmlurl"},
+		{name: "TrailingInitialism", in: "UserID", want: "user_id"},
+	}
+
+	for _, tt := range tests {
+		tc := tt
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tc.want, camelToSnake(tc.in))
+		})
+	}
+}
@@ -0,0 +1,26 @@
+package annotated
+
+import "github.com/ming-0x0/hexago/internal/shared/undefined"
+
+//accessor:generate -equal -text
+type Code struct {
+	value int64
+}
+
+func (c *Code) validate() error {
+	return nil
+}
+
+//accessor:generate -builder -equal -clone -json
+type Product struct {
+	code  Code
+	name  string
+	tags  []string
+	codes []Code
+	note  undefined.Undefined[string] `accessor:"setter,with"`
+}
+
+// not annotated
+type Ignored struct {
+	value string
+}
@@ -0,0 +1,29 @@
+package clone
+
+type Tag struct {
+	Name string
+}
+
+type inner struct {
+	values []int
+	count  int
+}
+
+type Node struct {
+	tags   []*Tag
+	byName map[string]*Tag
+	matrix [][]int
+	inner  inner
+	pairs  [2][]string
+	opt    *[]string
+	ref    **inner
+}
+
+type Cycle struct {
+	next *link
+}
+
+type link struct {
+	value int
+	next  *link
+}
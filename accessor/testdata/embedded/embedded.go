@@ -0,0 +1,20 @@
+package embedded
+
+type base struct {
+	id    string
+	count int
+}
+
+type Counter struct {
+	base
+	name string
+}
+
+type hidden struct {
+	value int
+}
+
+type Opaque struct {
+	name   string
+	hidden hidden
+}
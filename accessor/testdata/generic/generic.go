@@ -0,0 +1,18 @@
+package generic
+
+type Box[T any] struct {
+	Value T
+}
+
+type Map[K comparable, V any] map[K]V
+
+type Pair[K comparable, V any] struct {
+	key    K
+	values Map[K, V]
+	box    Box[V]
+	boxes  []Box[K]
+}
+
+func (p *Pair[K, V]) validate() error {
+	return nil
+}
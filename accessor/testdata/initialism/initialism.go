@@ -0,0 +1,12 @@
+package initialism
+
+type User struct {
+	id       string
+	userID   string
+	htmlURL  string
+	apiKey   string
+	jsonBody []byte
+	dbTS     int64
+	ulid     string
+	name     string
+}
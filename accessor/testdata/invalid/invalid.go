@@ -0,0 +1,27 @@
+package invalid
+
+type UnknownTag struct {
+	value string `accessor:"unknown"`
+}
+
+type ReadonlySetter struct {
+	value string `accessor:"readonly,setter"`
+}
+
+type NoPrivateField struct {
+	Value string
+}
+
+type TwoFields struct {
+	a string
+	b string
+}
+
+type DuplicateName struct {
+	a string `accessor:"getter=Value"`
+	b string `accessor:"getter=Value"`
+}
+
+type EqualName struct {
+	a string `accessor:"getter=Equal"`
+}
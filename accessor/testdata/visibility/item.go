@@ -0,0 +1,9 @@
+package visibility
+
+type Item struct {
+	SKU string
+}
+
+type line struct {
+	quantity int
+}
@@ -0,0 +1,23 @@
+package visibility
+
+import "time"
+
+type base struct {
+	createdAt time.Time
+	note      string
+}
+
+type Order struct {
+	base
+	Exported string
+	id       string `accessor:"readonly"`
+	note     string `accessor:"getter=Memo,setter"`
+	item     Item
+	items    map[string]*Item
+	line     line
+	lines    []line
+	byLine   map[string]line
+	onLine   func(line) error
+	meta     struct{ key string }
+	internal string `accessor:"skip"`
+}
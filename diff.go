@@ -0,0 +1,43 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+// diffContext is the number of unchanged lines shown around each change.
+const diffContext = 3
+
+// unifiedDiff returns a unified diff turning old into new, or "" when they are equal.
+func unifiedDiff(oldName, newName string, old, new []byte) (string, error) {
+	if bytes.Equal(old, new) {
+		return "", nil
+	}
+
+	return difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        splitLines(old),
+		B:        splitLines(new),
+		FromFile: oldName,
+		ToFile:   newName,
+		Context:  diffContext,
+	})
+}
+
+// splitLines splits b into lines that keep their line ending. A last line
+// without one is given one, so that it compares equal to the same line
+// followed by a newline.
+func splitLines(b []byte) []string {
+	if len(b) == 0 {
+		return nil
+	}
+
+	lines := strings.SplitAfter(string(b), "\n")
+	if last := len(lines) - 1; lines[last] == "" {
+		lines = lines[:last]
+	} else {
+		lines[last] += "\n"
+	}
+	return lines
+}
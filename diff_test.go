@@ -0,0 +1,127 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// numberedLines returns the lines "1" to "n".
+func numberedLines(n int) []string {
+	lines := make([]string, n)
+	for i := range lines {
+		lines[i] = fmt.Sprint(i + 1)
+	}
+	return lines
+}
+
+func replaceLines(lines []string, replacements map[int]string) []string {
+	ret := make([]string, len(lines))
+	copy(ret, lines)
+	for i, line := range replacements {
+		ret[i-1] = line
+	}
+	return ret
+}
+
+func text(lines []string) string {
+	if len(lines) == 0 {
+		return ""
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+func TestUnifiedDiff(t *testing.T) {
+	t.Parallel()
+
+	lines := numberedLines(20)
+
+	tests := []struct {
+		name string
+		old  string
+		new  string
+		want string
+	}{
+		{
+			name: "Equal",
+			old:  text(lines),
+			new:  text(lines),
+			want: "",
+		},
+		{
+			name: "BothEmpty",
+			old:  "",
+			new:  "",
+			want: "",
+		},
+		{
+			name: "Changed",
+			old:  text(lines),
+			new:  text(replaceLines(lines, map[int]string{10: "ten"})),
+			want: "--- a\n+++ b\n" +
+				"@@ -7,7 +7,7 @@\n 7\n 8\n 9\n-10\n+ten\n 11\n 12\n 13\n",
+		},
+		{
+			name: "ChangedFirstLine",
+			old:  text(lines[:5]),
+			new:  text(replaceLines(lines[:5], map[int]string{1: "one"})),
+			want: "--- a\n+++ b\n" +
+				"@@ -1,4 +1,4 @@\n-1\n+one\n 2\n 3\n 4\n",
+		},
+		{
+			name: "NearbyChangesMerged",
+			old:  text(lines),
+			new:  text(replaceLines(lines, map[int]string{5: "five", 11: "eleven"})),
+			want: "--- a\n+++ b\n" +
+				"@@ -2,13 +2,13 @@\n 2\n 3\n 4\n-5\n+five\n 6\n 7\n 8\n 9\n 10\n-11\n+eleven\n 12\n 13\n 14\n",
+		},
+		{
+			name: "DistantChangesSplit",
+			old:  text(lines),
+			new:  text(replaceLines(lines, map[int]string{3: "three", 18: "eighteen"})),
+			want: "--- a\n+++ b\n" +
+				"@@ -1,6 +1,6 @@\n 1\n 2\n-3\n+three\n 4\n 5\n 6\n" +
+				"@@ -15,6 +15,6 @@\n 15\n 16\n 17\n-18\n+eighteen\n 19\n 20\n",
+		},
+		{
+			name: "Inserted",
+			old:  text(lines[:6]),
+			new:  text(append(append(append([]string{}, lines[:3]...), "new"), lines[3:6]...)),
+			want: "--- a\n+++ b\n" +
+				"@@ -1,6 +1,7 @@\n 1\n 2\n 3\n+new\n 4\n 5\n 6\n",
+		},
+		{
+			name: "AddedFile",
+			old:  "",
+			new:  text(lines[:2]),
+			want: "--- a\n+++ b\n" +
+				"@@ -0,0 +1,2 @@\n+1\n+2\n",
+		},
+		{
+			name: "RemovedFile",
+			old:  text(lines[:2]),
+			new:  "",
+			want: "--- a\n+++ b\n" +
+				"@@ -1,2 +0,0 @@\n-1\n-2\n",
+		},
+		{
+			name: "MissingFinalNewline",
+			old:  "1\n2",
+			new:  "1\n3\n",
+			want: "--- a\n+++ b\n" +
+				"@@ -1,2 +1,2 @@\n 1\n-2\n+3\n",
+		},
+	}
+
+	for _, tt := range tests {
+		tc := tt
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			diff, err := unifiedDiff("a", "b", []byte(tc.old), []byte(tc.new))
+			assert.NoError(t, err)
+			assert.Equal(t, tc.want, diff)
+		})
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/ming-0x0/hexago/internal/customer/domain/service_type"
 	"github.com/ming-0x0/hexago/internal/customer/domain/status"
 	"github.com/ming-0x0/hexago/internal/shared/domain/email"
+	sharedEntity "github.com/ming-0x0/hexago/internal/shared/entity"
 )
 
 type CustomerRepositoryAdapter struct{}
@@ -36,6 +37,7 @@ func (a *CustomerRepositoryAdapter) ToDomain(e *entity.Customer) (*customer.Cust
 		e.Note,
 		*serviceType,
 		*status,
+		e.Version,
 	)
 }
 
@@ -50,6 +52,7 @@ func (a *CustomerRepositoryAdapter) ToEntity(d *customer.Customer) (*entity.Cust
 		Note:         d.Note(),
 		ServiceType:  d.ServiceType().Value(),
 		Status:       d.Status().Value(),
+		Versioned:    sharedEntity.Versioned{Version: d.Version()},
 	}, nil
 }
 
@@ -8,6 +8,7 @@ import (
 	"github.com/ming-0x0/hexago/internal/customer/domain/service_type"
 	"github.com/ming-0x0/hexago/internal/customer/domain/status"
 	"github.com/ming-0x0/hexago/internal/shared/domain/email"
+	sharedEntity "github.com/ming-0x0/hexago/internal/shared/entity"
 	"github.com/ming-0x0/hexago/internal/shared/undefined"
 	"github.com/stretchr/testify/assert"
 )
@@ -38,6 +39,7 @@ func TestCustomerRepositoryAdapter_ToDomain(t *testing.T) {
 					Note:         undefined.Undefined[string]{},
 					ServiceType:  1,
 					Status:       1,
+					Versioned:    sharedEntity.Versioned{Version: 1},
 				},
 			},
 			want: func() *customer.Customer {
@@ -60,6 +62,7 @@ func TestCustomerRepositoryAdapter_ToDomain(t *testing.T) {
 						status, _ := status.New(1)
 						return *status
 					}(),
+					1,
 				)
 
 				return domain
@@ -79,6 +82,7 @@ func TestCustomerRepositoryAdapter_ToDomain(t *testing.T) {
 					Note:         undefined.Undefined[string]{},
 					ServiceType:  1,
 					Status:       1,
+					Versioned:    sharedEntity.Versioned{Version: 1},
 				},
 			},
 			want:      nil,
@@ -97,6 +101,7 @@ func TestCustomerRepositoryAdapter_ToDomain(t *testing.T) {
 					Note:         undefined.Undefined[string]{},
 					ServiceType:  4,
 					Status:       1,
+					Versioned:    sharedEntity.Versioned{Version: 1},
 				},
 			},
 			want:      nil,
@@ -115,6 +120,7 @@ func TestCustomerRepositoryAdapter_ToDomain(t *testing.T) {
 					Note:         undefined.Undefined[string]{},
 					ServiceType:  1,
 					Status:       3,
+					Versioned:    sharedEntity.Versioned{Version: 1},
 				},
 			},
 			want:      nil,
@@ -163,6 +169,7 @@ func TestCustomerRepositoryAdapter_ToDomains(t *testing.T) {
 						Note:         undefined.Undefined[string]{},
 						ServiceType:  1,
 						Status:       1,
+						Versioned:    sharedEntity.Versioned{Version: 1},
 					},
 					{
 						ID:           "customer_id_2",
@@ -174,6 +181,7 @@ func TestCustomerRepositoryAdapter_ToDomains(t *testing.T) {
 						Note:         undefined.Undefined[string]{},
 						ServiceType:  2,
 						Status:       2,
+						Versioned:    sharedEntity.Versioned{Version: 1},
 					},
 				},
 			},
@@ -197,6 +205,7 @@ func TestCustomerRepositoryAdapter_ToDomains(t *testing.T) {
 						status, _ := status.New(1)
 						return *status
 					}(),
+					1,
 				)
 				domain2, _ := customer.FromRepository(
 					"customer_id_2",
@@ -217,6 +226,7 @@ func TestCustomerRepositoryAdapter_ToDomains(t *testing.T) {
 						status, _ := status.New(2)
 						return *status
 					}(),
+					1,
 				)
 				return []*customer.Customer{domain1, domain2}
 			}(),
@@ -236,6 +246,7 @@ func TestCustomerRepositoryAdapter_ToDomains(t *testing.T) {
 						Note:         undefined.Undefined[string]{},
 						ServiceType:  1,
 						Status:       1,
+						Versioned:    sharedEntity.Versioned{Version: 1},
 					},
 					{
 						ID:           "customer_id_2",
@@ -247,6 +258,7 @@ func TestCustomerRepositoryAdapter_ToDomains(t *testing.T) {
 						Note:         undefined.Undefined[string]{},
 						ServiceType:  2,
 						Status:       2,
+						Versioned:    sharedEntity.Versioned{Version: 1},
 					},
 				},
 			},
@@ -305,6 +317,7 @@ func TestCustomerRepositoryAdapter_ToEntities(t *testing.T) {
 							status, _ := status.New(1)
 							return *status
 						}(),
+						1,
 					)
 					domain2, _ := customer.FromRepository(
 						"customer_id_2",
@@ -325,6 +338,7 @@ func TestCustomerRepositoryAdapter_ToEntities(t *testing.T) {
 							status, _ := status.New(2)
 							return *status
 						}(),
+						1,
 					)
 					return []*customer.Customer{domain1, domain2}
 				}(),
@@ -340,6 +354,7 @@ func TestCustomerRepositoryAdapter_ToEntities(t *testing.T) {
 					Note:         undefined.Undefined[string]{},
 					ServiceType:  1,
 					Status:       1,
+					Versioned:    sharedEntity.Versioned{Version: 1},
 				},
 				{
 					ID:           "customer_id_2",
@@ -351,6 +366,7 @@ func TestCustomerRepositoryAdapter_ToEntities(t *testing.T) {
 					Note:         undefined.Undefined[string]{},
 					ServiceType:  2,
 					Status:       2,
+					Versioned:    sharedEntity.Versioned{Version: 1},
 				},
 			},
 			assertion: assert.NoError,
@@ -406,6 +422,7 @@ func TestCustomerRepositoryAdapter_ToEntity(t *testing.T) {
 							status, _ := status.New(1)
 							return *status
 						}(),
+						1,
 					)
 
 					return domain
@@ -422,6 +439,7 @@ func TestCustomerRepositoryAdapter_ToEntity(t *testing.T) {
 					Note:         undefined.Undefined[string]{},
 					ServiceType:  1,
 					Status:       1,
+					Versioned:    sharedEntity.Versioned{Version: 1},
 				}
 			}(),
 			assertion: assert.NoError,
@@ -16,8 +16,9 @@ func New(
 	db *gorm.DB,
 	logger *logrus.Logger,
 	adapter *CustomerRepositoryAdapter,
+	opts ...repository.Option,
 ) *CustomerRepository {
 	return &CustomerRepository{
-		Repository: repository.NewRepository(db, logger, adapter),
+		Repository: repository.NewRepository(db, logger, adapter, opts...),
 	}
 }
@@ -18,4 +18,9 @@ type Customer struct {
 	ServiceType  int64                       `gorm:"column:service_type;type:tinyint(1) unsigned;not null"`
 	Status       int64                       `gorm:"column:status;type:tinyint(1) unsigned;not null;default:2"`
 	entity.BaseEntityWithDeleted
+	entity.Versioned
+}
+
+func (c *Customer) SortableColumns() []string {
+	return []string{"customer_name", entity.CreatedAtColumn, "status"}
 }
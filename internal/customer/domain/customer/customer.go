@@ -20,17 +20,18 @@ const (
 
 type ID string
 
-//go:generate sh -c "$(go list -m -f '{{.Dir}}')/bin/accessor -type=Customer"
+//accessor:generate -builder -validated-setters -equal -clone -json
 type Customer struct {
-	id           ID
+	id           ID `accessor:"readonly"`
 	customerName string
-	email        email.Email
+	email        email.Email `accessor:"readonly"`
 	phoneNumber  string
 	companyName  undefined.Undefined[string]
 	message      undefined.Undefined[string]
-	note         undefined.Undefined[string]
+	note         undefined.Undefined[string] `accessor:"setter,with"`
 	serviceType  service_type.ServiceType
-	status       status.Status
+	status       status.Status `accessor:"setter,with"`
+	version      int64         `accessor:"readonly"`
 }
 
 func New(
@@ -72,6 +73,7 @@ func FromRepository(
 	note undefined.Undefined[string],
 	serviceType service_type.ServiceType,
 	status status.Status,
+	version int64,
 ) (*Customer, error) {
 	customer := &Customer{
 		id:           id,
@@ -83,6 +85,7 @@ func FromRepository(
 		note:         note,
 		serviceType:  serviceType,
 		status:       status,
+		version:      version,
 	}
 
 	if err := customer.validate(); err != nil {
@@ -1,8 +1,10 @@
-// Code generated by "accessor -type=Customer"; DO NOT EDIT.
+// Code generated by "accessor -type=Customer -builder -validated-setters -equal -clone -json"; DO NOT EDIT.
 
 package customer
 
 import (
+	"encoding/json"
+
 	"github.com/ming-0x0/hexago/internal/customer/domain/service_type"
 	"github.com/ming-0x0/hexago/internal/customer/domain/status"
 	"github.com/ming-0x0/hexago/internal/shared/domain/email"
@@ -44,6 +46,26 @@ func (t Customer) Note() undefined.Undefined[string] {
 	return t.note
 }
 
+// SetNote set v to note if it passes validate method
+func (t *Customer) SetNote(v undefined.Undefined[string]) error {
+	old := t.note
+	t.note = v
+	if err := t.validate(); err != nil {
+		t.note = old
+		return err
+	}
+	return nil
+}
+
+// WithNote return copy with v set to note validated by validate method
+func (t Customer) WithNote(v undefined.Undefined[string]) (Customer, error) {
+	t.note = v
+	if err := t.validate(); err != nil {
+		return Customer{}, err
+	}
+	return t, nil
+}
+
 // ServiceType return serviceType value
 func (t Customer) ServiceType() service_type.ServiceType {
 	return t.serviceType
@@ -53,3 +75,189 @@ func (t Customer) ServiceType() service_type.ServiceType {
 func (t Customer) Status() status.Status {
 	return t.status
 }
+
+// SetStatus set v to status if it passes validate method
+func (t *Customer) SetStatus(v status.Status) error {
+	old := t.status
+	t.status = v
+	if err := t.validate(); err != nil {
+		t.status = old
+		return err
+	}
+	return nil
+}
+
+// WithStatus return copy with v set to status validated by validate method
+func (t Customer) WithStatus(v status.Status) (Customer, error) {
+	t.status = v
+	if err := t.validate(); err != nil {
+		return Customer{}, err
+	}
+	return t, nil
+}
+
+// Version return version value
+func (t Customer) Version() int64 {
+	return t.version
+}
+
+// Equal return whether t and other are equal
+func (t Customer) Equal(other Customer) bool {
+	return t.id == other.id &&
+		t.customerName == other.customerName &&
+		t.email.Equal(other.email) &&
+		t.phoneNumber == other.phoneNumber &&
+		t.companyName.Equal(other.companyName) &&
+		t.message.Equal(other.message) &&
+		t.note.Equal(other.note) &&
+		t.serviceType.Equal(other.serviceType) &&
+		t.status.Equal(other.status) &&
+		t.version == other.version
+}
+
+// Clone return deep copy of t
+func (t Customer) Clone() Customer {
+	c := t
+	return c
+}
+
+// MarshalJSON implement json.Marshaler
+func (t Customer) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		ID           ID                          `json:"id"`
+		CustomerName string                      `json:"customer_name"`
+		Email        email.Email                 `json:"email"`
+		PhoneNumber  string                      `json:"phone_number"`
+		CompanyName  undefined.Undefined[string] `json:"company_name,omitzero"`
+		Message      undefined.Undefined[string] `json:"message,omitzero"`
+		Note         undefined.Undefined[string] `json:"note,omitzero"`
+		ServiceType  service_type.ServiceType    `json:"service_type"`
+		Status       status.Status               `json:"status"`
+		Version      int64                       `json:"version"`
+	}{
+		ID:           t.id,
+		CustomerName: t.customerName,
+		Email:        t.email,
+		PhoneNumber:  t.phoneNumber,
+		CompanyName:  t.companyName,
+		Message:      t.message,
+		Note:         t.note,
+		ServiceType:  t.serviceType,
+		Status:       t.status,
+		Version:      t.version,
+	})
+}
+
+// UnmarshalJSON implement json.Unmarshaler
+func (t *Customer) UnmarshalJSON(data []byte) error {
+	var v struct {
+		ID           ID                          `json:"id"`
+		CustomerName string                      `json:"customer_name"`
+		Email        email.Email                 `json:"email"`
+		PhoneNumber  string                      `json:"phone_number"`
+		CompanyName  undefined.Undefined[string] `json:"company_name,omitzero"`
+		Message      undefined.Undefined[string] `json:"message,omitzero"`
+		Note         undefined.Undefined[string] `json:"note,omitzero"`
+		ServiceType  service_type.ServiceType    `json:"service_type"`
+		Status       status.Status               `json:"status"`
+		Version      int64                       `json:"version"`
+	}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	var u Customer
+	u.id = v.ID
+	u.customerName = v.CustomerName
+	u.email = v.Email
+	u.phoneNumber = v.PhoneNumber
+	u.companyName = v.CompanyName
+	u.message = v.Message
+	u.note = v.Note
+	u.serviceType = v.ServiceType
+	u.status = v.Status
+	u.version = v.Version
+	if err := u.validate(); err != nil {
+		return err
+	}
+	*t = u
+	return nil
+}
+
+// CustomerBuilder build Customer value
+type CustomerBuilder struct {
+	t Customer
+}
+
+// NewCustomerBuilder return new CustomerBuilder
+func NewCustomerBuilder() *CustomerBuilder {
+	return &CustomerBuilder{}
+}
+
+// WithID set v to id
+func (b *CustomerBuilder) WithID(v ID) *CustomerBuilder {
+	b.t.id = v
+	return b
+}
+
+// WithCustomerName set v to customerName
+func (b *CustomerBuilder) WithCustomerName(v string) *CustomerBuilder {
+	b.t.customerName = v
+	return b
+}
+
+// WithEmail set v to email
+func (b *CustomerBuilder) WithEmail(v email.Email) *CustomerBuilder {
+	b.t.email = v
+	return b
+}
+
+// WithPhoneNumber set v to phoneNumber
+func (b *CustomerBuilder) WithPhoneNumber(v string) *CustomerBuilder {
+	b.t.phoneNumber = v
+	return b
+}
+
+// WithCompanyName set v to companyName
+func (b *CustomerBuilder) WithCompanyName(v undefined.Undefined[string]) *CustomerBuilder {
+	b.t.companyName = v
+	return b
+}
+
+// WithMessage set v to message
+func (b *CustomerBuilder) WithMessage(v undefined.Undefined[string]) *CustomerBuilder {
+	b.t.message = v
+	return b
+}
+
+// WithNote set v to note
+func (b *CustomerBuilder) WithNote(v undefined.Undefined[string]) *CustomerBuilder {
+	b.t.note = v
+	return b
+}
+
+// WithServiceType set v to serviceType
+func (b *CustomerBuilder) WithServiceType(v service_type.ServiceType) *CustomerBuilder {
+	b.t.serviceType = v
+	return b
+}
+
+// WithStatus set v to status
+func (b *CustomerBuilder) WithStatus(v status.Status) *CustomerBuilder {
+	b.t.status = v
+	return b
+}
+
+// WithVersion set v to version
+func (b *CustomerBuilder) WithVersion(v int64) *CustomerBuilder {
+	b.t.version = v
+	return b
+}
+
+// Build return Customer value validated by validate method
+func (b *CustomerBuilder) Build() (*Customer, error) {
+	t := b.t
+	if err := t.validate(); err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
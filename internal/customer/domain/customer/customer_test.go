@@ -1,6 +1,7 @@
 package customer
 
 import (
+	"encoding/json"
 	"strings"
 	"testing"
 
@@ -159,6 +160,7 @@ func TestNew(t *testing.T) {
 				assert.Equal(t, tc.args.note, got.note)
 				assert.Equal(t, tc.args.serviceType, got.serviceType)
 				assert.Equal(t, tc.args.status, got.status)
+				assert.Zero(t, got.version)
 			}
 		})
 	}
@@ -183,6 +185,7 @@ func TestFromRepository(t *testing.T) {
 		note         undefined.Undefined[string]
 		serviceType  service_type.ServiceType
 		status       status.Status
+		version      int64
 	}
 
 	tests := []struct {
@@ -202,6 +205,7 @@ func TestFromRepository(t *testing.T) {
 				note:         undefinedStr,
 				serviceType:  *validServiceType,
 				status:       *validStatus,
+				version:      3,
 			},
 			assertion: assert.NoError,
 		},
@@ -325,6 +329,7 @@ func TestFromRepository(t *testing.T) {
 				tc.args.note,
 				tc.args.serviceType,
 				tc.args.status,
+				tc.args.version,
 			)
 			tc.assertion(t, err)
 			if err == nil {
@@ -337,7 +342,258 @@ func TestFromRepository(t *testing.T) {
 				assert.Equal(t, tc.args.note, got.note)
 				assert.Equal(t, tc.args.serviceType, got.serviceType)
 				assert.Equal(t, tc.args.status, got.status)
+				assert.Equal(t, tc.args.version, got.version)
 			}
 		})
 	}
 }
+
+func TestCustomerBuilder_Build(t *testing.T) {
+	t.Parallel()
+
+	validEmail, _ := email.New("test@example.com")
+	validServiceType, _ := service_type.New(1)
+	validStatus, _ := status.New(1)
+
+	tests := []struct {
+		name      string
+		builder   func() *CustomerBuilder
+		assertion assert.ErrorAssertionFunc
+	}{
+		{
+			name: "Valid",
+			builder: func() *CustomerBuilder {
+				return NewCustomerBuilder().
+					WithID("customer_id").
+					WithCustomerName("test").
+					WithEmail(*validEmail).
+					WithPhoneNumber("1234567890").
+					WithServiceType(*validServiceType).
+					WithStatus(*validStatus)
+			},
+			assertion: assert.NoError,
+		},
+		{
+			name: "Invalid_PhoneNumber",
+			builder: func() *CustomerBuilder {
+				return NewCustomerBuilder().
+					WithID("customer_id").
+					WithCustomerName("test").
+					WithEmail(*validEmail).
+					WithPhoneNumber("123").
+					WithServiceType(*validServiceType).
+					WithStatus(*validStatus)
+			},
+			assertion: assert.Error,
+		},
+		{
+			name: "Invalid_Empty",
+			builder: func() *CustomerBuilder {
+				return NewCustomerBuilder()
+			},
+			assertion: assert.Error,
+		},
+	}
+
+	for _, tt := range tests {
+		tc := tt
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := tc.builder().Build()
+			tc.assertion(t, err)
+			if err == nil {
+				assert.Equal(t, ID("customer_id"), got.ID())
+				assert.Equal(t, "test", got.CustomerName())
+				assert.Equal(t, *validEmail, got.Email())
+				assert.Equal(t, "1234567890", got.PhoneNumber())
+				assert.Equal(t, *validServiceType, got.ServiceType())
+				assert.Equal(t, *validStatus, got.Status())
+			}
+		})
+	}
+}
+
+func TestCustomer_WithNote(t *testing.T) {
+	t.Parallel()
+
+	validEmail, _ := email.New("test@example.com")
+	validServiceType, _ := service_type.New(1)
+	validStatus, _ := status.New(1)
+
+	type args struct {
+		note undefined.Undefined[string]
+	}
+
+	tests := []struct {
+		name      string
+		args      args
+		assertion assert.ErrorAssertionFunc
+	}{
+		{
+			name:      "Valid",
+			args:      args{note: undefined.New("note")},
+			assertion: assert.NoError,
+		},
+		{
+			name:      "Invalid_Note",
+			args:      args{note: undefined.New(strings.Repeat("a", maxNoteLength+1))},
+			assertion: assert.Error,
+		},
+	}
+
+	for _, tt := range tests {
+		tc := tt
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			original, _ := FromRepository(
+				"customer_id",
+				"test",
+				*validEmail,
+				"1234567890",
+				undefined.Undefined[string]{},
+				undefined.Undefined[string]{},
+				undefined.Undefined[string]{},
+				*validServiceType,
+				*validStatus,
+				1,
+			)
+
+			got, err := original.WithNote(tc.args.note)
+			tc.assertion(t, err)
+			assert.True(t, original.Note().IsUndefined())
+			if err == nil {
+				assert.Equal(t, tc.args.note, got.Note())
+				assert.Equal(t, original.ID(), got.ID())
+			}
+		})
+	}
+}
+
+func TestCustomer_SetNote(t *testing.T) {
+	t.Parallel()
+
+	validEmail, _ := email.New("test@example.com")
+	validServiceType, _ := service_type.New(1)
+	validStatus, _ := status.New(1)
+	originalNote := undefined.New("original")
+
+	type args struct {
+		note undefined.Undefined[string]
+	}
+
+	tests := []struct {
+		name      string
+		args      args
+		want      undefined.Undefined[string]
+		assertion assert.ErrorAssertionFunc
+	}{
+		{
+			name:      "Valid",
+			args:      args{note: undefined.New("note")},
+			want:      undefined.New("note"),
+			assertion: assert.NoError,
+		},
+		{
+			name:      "Invalid_Note_RolledBack",
+			args:      args{note: undefined.New(strings.Repeat("a", maxNoteLength+1))},
+			want:      originalNote,
+			assertion: assert.Error,
+		},
+	}
+
+	for _, tt := range tests {
+		tc := tt
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			c, _ := FromRepository(
+				"customer_id",
+				"test",
+				*validEmail,
+				"1234567890",
+				undefined.Undefined[string]{},
+				undefined.Undefined[string]{},
+				originalNote,
+				*validServiceType,
+				*validStatus,
+				1,
+			)
+
+			err := c.SetNote(tc.args.note)
+			tc.assertion(t, err)
+			assert.Equal(t, tc.want, c.Note())
+		})
+	}
+}
+
+func TestCustomer_Equal(t *testing.T) {
+	t.Parallel()
+
+	validEmail, _ := email.New("test@example.com")
+	validServiceType, _ := service_type.New(1)
+	validStatus, _ := status.New(1)
+
+	c, _ := FromRepository(
+		"customer_id",
+		"test",
+		*validEmail,
+		"1234567890",
+		undefined.New("company"),
+		undefined.Undefined[string]{},
+		undefined.Undefined[string]{},
+		*validServiceType,
+		*validStatus,
+		1,
+	)
+
+	clone := c.Clone()
+	assert.True(t, c.Equal(clone))
+
+	changed, err := clone.WithNote(undefined.New("note"))
+	assert.NoError(t, err)
+	assert.False(t, c.Equal(changed))
+}
+
+func TestCustomer_JSON(t *testing.T) {
+	t.Parallel()
+
+	validEmail, _ := email.New("test@example.com")
+	validServiceType, _ := service_type.New(1)
+	validStatus, _ := status.New(1)
+
+	c, _ := FromRepository(
+		"customer_id",
+		"test",
+		*validEmail,
+		"1234567890",
+		undefined.New("company"),
+		undefined.Undefined[string]{},
+		undefined.Undefined[string]{},
+		*validServiceType,
+		*validStatus,
+		1,
+	)
+
+	data, err := json.Marshal(c)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{
+		"id": "customer_id",
+		"customer_name": "test",
+		"email": "test@example.com",
+		"phone_number": "1234567890",
+		"company_name": "company",
+		"service_type": "1",
+		"status": "1",
+		"version": 1
+	}`, string(data))
+
+	var got Customer
+	assert.NoError(t, json.Unmarshal(data, &got))
+	assert.True(t, c.Equal(got))
+
+	invalid := strings.Replace(string(data), `"1234567890"`, `"123"`, 1)
+	assert.Error(t, json.Unmarshal([]byte(invalid), &got))
+	assert.True(t, c.Equal(got))
+
+	invalidStatus := strings.Replace(string(data), `"status":"1"`, `"status":"3"`, 1)
+	assert.Error(t, json.Unmarshal([]byte(invalidStatus), &got))
+}
@@ -11,7 +11,7 @@ var (
 	KhoaHoc   = ServiceType{value: 3}
 )
 
-//go:generate sh -c "$(go list -m -f '{{.Dir}}')/bin/accessor -type=ServiceType"
+//accessor:generate -equal -text
 type ServiceType struct {
 	value int64
 }
@@ -1,8 +1,34 @@
-// Code generated by "accessor -type=ServiceType"; DO NOT EDIT.
+// Code generated by "accessor -type=ServiceType -equal -text"; DO NOT EDIT.
 
 package service_type
 
+import "strconv"
+
 // Value return value value
 func (t ServiceType) Value() int64 {
 	return t.value
 }
+
+// Equal return whether t and other are equal
+func (t ServiceType) Equal(other ServiceType) bool {
+	return t.value == other.value
+}
+
+// MarshalText implement encoding.TextMarshaler
+func (t ServiceType) MarshalText() ([]byte, error) {
+	return strconv.AppendInt(nil, int64(t.value), 10), nil
+}
+
+// UnmarshalText implement encoding.TextUnmarshaler
+func (t *ServiceType) UnmarshalText(text []byte) error {
+	v, err := strconv.ParseInt(string(text), 10, 64)
+	if err != nil {
+		return err
+	}
+	u := ServiceType{value: int64(v)}
+	if err := u.validate(); err != nil {
+		return err
+	}
+	*t = u
+	return nil
+}
@@ -10,7 +10,7 @@ var (
 	Unreplied = Status{value: 2}
 )
 
-//go:generate sh -c "$(go list -m -f '{{.Dir}}')/bin/accessor -type=Status"
+//accessor:generate -equal -text
 type Status struct {
 	value int64
 }
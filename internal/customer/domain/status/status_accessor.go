@@ -1,8 +1,34 @@
-// Code generated by "accessor -type=Status"; DO NOT EDIT.
+// Code generated by "accessor -type=Status -equal -text"; DO NOT EDIT.
 
 package status
 
+import "strconv"
+
 // Value return value value
 func (t Status) Value() int64 {
 	return t.value
 }
+
+// Equal return whether t and other are equal
+func (t Status) Equal(other Status) bool {
+	return t.value == other.value
+}
+
+// MarshalText implement encoding.TextMarshaler
+func (t Status) MarshalText() ([]byte, error) {
+	return strconv.AppendInt(nil, int64(t.value), 10), nil
+}
+
+// UnmarshalText implement encoding.TextUnmarshaler
+func (t *Status) UnmarshalText(text []byte) error {
+	v, err := strconv.ParseInt(string(text), 10, 64)
+	if err != nil {
+		return err
+	}
+	u := Status{value: int64(v)}
+	if err := u.validate(); err != nil {
+		return err
+	}
+	*t = u
+	return nil
+}
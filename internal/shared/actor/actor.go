@@ -0,0 +1,24 @@
+package actor
+
+import "context"
+
+type IDKey string
+
+const (
+	ID IDKey = "actor_id"
+)
+
+// WithID returns a copy of ctx carrying the ID of the acting user.
+func WithID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, ID, id)
+}
+
+func IDFromContext(ctx context.Context) (string, bool) {
+	v := ctx.Value(ID)
+	if v != nil {
+		if id, ok := v.(string); ok && id != "" {
+			return id, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,54 @@
+package actor
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIDFromContext(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		ctx      context.Context
+		expected string
+		ok       bool
+	}{
+		{
+			name:     "WithID",
+			ctx:      WithID(context.Background(), "01ARZ3NDEKTSV4RRFFQ69G5FAV"),
+			expected: "01ARZ3NDEKTSV4RRFFQ69G5FAV",
+			ok:       true,
+		},
+		{
+			name:     "NoID",
+			ctx:      context.Background(),
+			expected: "",
+			ok:       false,
+		},
+		{
+			name:     "EmptyID",
+			ctx:      WithID(context.Background(), ""),
+			expected: "",
+			ok:       false,
+		},
+		{
+			name:     "InvalidType",
+			ctx:      context.WithValue(context.Background(), ID, 1),
+			expected: "",
+			ok:       false,
+		},
+	}
+
+	for _, tt := range tests {
+		tc := tt
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			id, ok := IDFromContext(tc.ctx)
+			assert.Equal(t, tc.expected, id)
+			assert.Equal(t, tc.ok, ok)
+		})
+	}
+}
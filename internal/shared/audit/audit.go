@@ -0,0 +1,132 @@
+package audit
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/ming-0x0/hexago/internal/shared/actor"
+	"github.com/ming-0x0/hexago/internal/shared/transaction"
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+var LogTable = "audit_logs"
+
+type Action string
+
+const (
+	Create Action = "create"
+	Update Action = "update"
+	Delete Action = "delete"
+)
+
+// Change is the value of a column before and after a write.
+type Change struct {
+	Before any `json:"before"`
+	After  any `json:"after"`
+}
+
+// Changes are the changed columns of a write by column name.
+type Changes map[string]Change
+
+// Log is an entry of the append-only audit_logs table.
+type Log struct {
+	ID         uint64    `gorm:"column:id;primaryKey;autoIncrement"`
+	EntityType string    `gorm:"column:entity_type;type:varchar(64);not null;index:idx_audit_logs_entity"`
+	EntityID   string    `gorm:"column:entity_id;type:varchar(64);not null;index:idx_audit_logs_entity"`
+	Action     Action    `gorm:"column:action;type:varchar(16);not null"`
+	ActorID    string    `gorm:"column:actor_id;type:char(26)"`
+	Changes    Changes   `gorm:"column:changes;type:json;not null;serializer:json"`
+	CreatedAt  time.Time `gorm:"column:created_at;type:datetime;not null;default:current_timestamp"`
+}
+
+func (Log) TableName() string {
+	return LogTable
+}
+
+// NewLog returns the log of action on an entity of schema s, given its values
+// before and after the write. before is nil for Create and after is nil for
+// Delete. It returns nil when no column changed.
+func NewLog(ctx context.Context, s *schema.Schema, action Action, before, after any) *Log {
+	changes := Diff(ctx, s, before, after)
+	if len(changes) == 0 {
+		return nil
+	}
+
+	log := &Log{
+		EntityType: s.Table,
+		Action:     action,
+		Changes:    changes,
+	}
+	if id, ok := actor.IDFromContext(ctx); ok {
+		log.ActorID = id
+	}
+
+	entity := after
+	if entity == nil {
+		entity = before
+	}
+	if pk := s.PrioritizedPrimaryField; pk != nil {
+		id, _ := pk.ValueOf(ctx, reflect.ValueOf(entity))
+		log.EntityID = fmt.Sprint(id)
+	}
+
+	return log
+}
+
+// Diff returns the columns of schema s whose values differ between the
+// entities before and after, either of which may be nil.
+func Diff(ctx context.Context, s *schema.Schema, before, after any) Changes {
+	changes := Changes{}
+	for _, field := range s.Fields {
+		if field.DBName == "" {
+			continue
+		}
+
+		var change Change
+		if before != nil {
+			change.Before, _ = field.ValueOf(ctx, reflect.ValueOf(before))
+		}
+		if after != nil {
+			change.After, _ = field.ValueOf(ctx, reflect.ValueOf(after))
+		}
+
+		if !reflect.DeepEqual(change.Before, change.After) {
+			changes[field.DBName] = change
+		}
+	}
+
+	return changes
+}
+
+//go:generate go tool mockgen -destination mock/audit.go -package mock github.com/ming-0x0/hexago/internal/shared/audit RecorderInterface
+type RecorderInterface interface {
+	Record(ctx context.Context, logs ...*Log) error
+}
+
+// Recorder writes the logs to the audit_logs table, within the transaction of
+// ctx if any so that they are only kept along with the audited write.
+type Recorder struct {
+	db *gorm.DB
+}
+
+func NewRecorder(db *gorm.DB) *Recorder {
+	return &Recorder{
+		db: db,
+	}
+}
+
+func (r *Recorder) Record(ctx context.Context, logs ...*Log) error {
+	if len(logs) == 0 {
+		return nil
+	}
+
+	db := r.db.WithContext(ctx)
+	if tx, ok := transaction.TransactionFromContext(ctx); ok {
+		db = tx
+	}
+
+	return db.Create(logs).Error
+}
@@ -0,0 +1,183 @@
+package audit
+
+import (
+	"context"
+	"regexp"
+	"sync"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/ming-0x0/hexago/internal/shared/actor"
+	"github.com/ming-0x0/hexago/internal/shared/dbmocker"
+	"github.com/ming-0x0/hexago/internal/shared/transaction"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+type DummyEntity struct {
+	ID     string `gorm:"column:id;primaryKey"`
+	Name   string `gorm:"column:name"`
+	Status int    `gorm:"column:status"`
+}
+
+func TestNewLog(t *testing.T) {
+	t.Parallel()
+
+	s, err := schema.Parse(&DummyEntity{}, &sync.Map{}, schema.NamingStrategy{})
+	if err != nil {
+		t.Fatalf("error when parsing schema: %v", err)
+	}
+
+	const actorID = "01ARZ3NDEKTSV4RRFFQ69G5FAV"
+
+	tests := []struct {
+		name     string
+		ctx      context.Context
+		action   Action
+		before   any
+		after    any
+		expected *Log
+	}{
+		{
+			name:   "Create",
+			ctx:    actor.WithID(context.Background(), actorID),
+			action: Create,
+			after:  &DummyEntity{ID: "1", Name: "Test", Status: 1},
+			expected: &Log{
+				EntityType: "dummy_entities",
+				EntityID:   "1",
+				Action:     Create,
+				ActorID:    actorID,
+				Changes: Changes{
+					"id":     {After: "1"},
+					"name":   {After: "Test"},
+					"status": {After: 1},
+				},
+			},
+		},
+		{
+			name:   "Update_ChangedColumnsOnly",
+			ctx:    actor.WithID(context.Background(), actorID),
+			action: Update,
+			before: &DummyEntity{ID: "1", Name: "Test", Status: 1},
+			after:  &DummyEntity{ID: "1", Name: "Test", Status: 2},
+			expected: &Log{
+				EntityType: "dummy_entities",
+				EntityID:   "1",
+				Action:     Update,
+				ActorID:    actorID,
+				Changes:    Changes{"status": {Before: 1, After: 2}},
+			},
+		},
+		{
+			name:   "Delete_WithoutActor",
+			ctx:    context.Background(),
+			action: Delete,
+			before: &DummyEntity{ID: "1", Name: "Test"},
+			expected: &Log{
+				EntityType: "dummy_entities",
+				EntityID:   "1",
+				Action:     Delete,
+				Changes: Changes{
+					"id":     {Before: "1"},
+					"name":   {Before: "Test"},
+					"status": {Before: 0},
+				},
+			},
+		},
+		{
+			name:     "Unchanged",
+			ctx:      context.Background(),
+			action:   Update,
+			before:   &DummyEntity{ID: "1", Name: "Test"},
+			after:    &DummyEntity{ID: "1", Name: "Test"},
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		tc := tt
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			got := NewLog(tc.ctx, s, tc.action, tc.before, tc.after)
+			assert.Equal(t, tc.expected, got)
+		})
+	}
+}
+
+func TestRecorder_Record(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		logs      []*Log
+		inTx      bool
+		setupMock func(sqlmock.Sqlmock)
+		assertion assert.ErrorAssertionFunc
+	}{
+		{
+			name: "Success",
+			logs: []*Log{{EntityType: "dummy_entities", EntityID: "1", Action: Create, Changes: Changes{"name": {After: "Test"}}}},
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(regexp.QuoteMeta(
+					"INSERT INTO `audit_logs` (`entity_type`,`entity_id`,`action`,`actor_id`,`changes`) VALUES (?,?,?,?,?)",
+				)).WithArgs("dummy_entities", "1", Create, "", `{"name":{"before":null,"after":"Test"}}`).WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
+			},
+			assertion: assert.NoError,
+		},
+		{
+			name: "Success_InTransaction",
+			logs: []*Log{{EntityType: "dummy_entities", EntityID: "1", Action: Delete, Changes: Changes{"name": {Before: "Test"}}}},
+			inTx: true,
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec("INSERT INTO `audit_logs`").WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
+			},
+			assertion: assert.NoError,
+		},
+		{
+			name:      "Success_NoLog",
+			logs:      nil,
+			setupMock: func(mock sqlmock.Sqlmock) {},
+			assertion: assert.NoError,
+		},
+		{
+			name: "Failure_DBError",
+			logs: []*Log{{EntityType: "dummy_entities", EntityID: "1", Action: Create}},
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec("INSERT INTO `audit_logs`").WillReturnError(gorm.ErrInvalidField)
+				mock.ExpectRollback()
+			},
+			assertion: assert.Error,
+		},
+	}
+
+	for _, tt := range tests {
+		tc := tt
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			mockedDB, err := dbmocker.NewMockedDB()
+			if err != nil {
+				t.Fatalf("error when creating mock DB: %v", err)
+			}
+			defer mockedDB.DB.Close()
+
+			tc.setupMock(mockedDB.SqlMock)
+			recorder := NewRecorder(mockedDB.GormDB)
+			if tc.inTx {
+				err = transaction.NewTransaction(mockedDB.GormDB).Do(context.Background(), func(ctx context.Context) error {
+					return recorder.Record(ctx, tc.logs...)
+				})
+			} else {
+				err = recorder.Record(context.Background(), tc.logs...)
+			}
+			tc.assertion(t, err)
+			assert.NoError(t, mockedDB.SqlMock.ExpectationsWereMet())
+		})
+	}
+}
@@ -0,0 +1,61 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/ming-0x0/hexago/internal/shared/audit (interfaces: RecorderInterface)
+//
+// Generated by this command:
+//
+//	mockgen -destination mock/audit.go -package mock github.com/ming-0x0/hexago/internal/shared/audit RecorderInterface
+//
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	audit "github.com/ming-0x0/hexago/internal/shared/audit"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockRecorderInterface is a mock of RecorderInterface interface.
+type MockRecorderInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockRecorderInterfaceMockRecorder
+	isgomock struct{}
+}
+
+// MockRecorderInterfaceMockRecorder is the mock recorder for MockRecorderInterface.
+type MockRecorderInterfaceMockRecorder struct {
+	mock *MockRecorderInterface
+}
+
+// NewMockRecorderInterface creates a new mock instance.
+func NewMockRecorderInterface(ctrl *gomock.Controller) *MockRecorderInterface {
+	mock := &MockRecorderInterface{ctrl: ctrl}
+	mock.recorder = &MockRecorderInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRecorderInterface) EXPECT() *MockRecorderInterfaceMockRecorder {
+	return m.recorder
+}
+
+// Record mocks base method.
+func (m *MockRecorderInterface) Record(ctx context.Context, logs ...*audit.Log) error {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx}
+	for _, a := range logs {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Record", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Record indicates an expected call of Record.
+func (mr *MockRecorderInterfaceMockRecorder) Record(ctx any, logs ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx}, logs...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Record", reflect.TypeOf((*MockRecorderInterface)(nil).Record), varargs...)
+}
@@ -0,0 +1,86 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLRU(t *testing.T) {
+	t.Parallel()
+
+	type want struct {
+		key   string
+		value any
+		found bool
+	}
+
+	tests := []struct {
+		name     string
+		capacity int
+		run      func(ctx context.Context, c *LRU, advance func(time.Duration))
+		expected []want
+	}{
+		{
+			name:     "Get",
+			capacity: 2,
+			run: func(ctx context.Context, c *LRU, _ func(time.Duration)) {
+				c.Set(ctx, "a", 1, 0)
+				c.Set(ctx, "a", 2, 0)
+			},
+			expected: []want{{key: "a", value: 2, found: true}, {key: "b"}},
+		},
+		{
+			name:     "EvictsLeastRecentlyUsed",
+			capacity: 2,
+			run: func(ctx context.Context, c *LRU, _ func(time.Duration)) {
+				c.Set(ctx, "a", 1, 0)
+				c.Set(ctx, "b", 2, 0)
+				c.Get(ctx, "a")
+				c.Set(ctx, "c", 3, 0)
+			},
+			expected: []want{{key: "a", value: 1, found: true}, {key: "b"}, {key: "c", value: 3, found: true}},
+		},
+		{
+			name:     "Expires",
+			capacity: 2,
+			run: func(ctx context.Context, c *LRU, advance func(time.Duration)) {
+				c.Set(ctx, "a", 1, time.Minute)
+				c.Set(ctx, "b", 2, time.Hour)
+				advance(time.Minute)
+			},
+			expected: []want{{key: "a"}, {key: "b", value: 2, found: true}},
+		},
+		{
+			name:     "DeletePrefix",
+			capacity: 3,
+			run: func(ctx context.Context, c *LRU, _ func(time.Duration)) {
+				c.Set(ctx, "customers:1", 1, 0)
+				c.Set(ctx, "customers:2", 2, 0)
+				c.Set(ctx, "orders:1", 3, 0)
+				c.DeletePrefix(ctx, "customers:")
+			},
+			expected: []want{{key: "customers:1"}, {key: "customers:2"}, {key: "orders:1", value: 3, found: true}},
+		},
+	}
+
+	for _, tt := range tests {
+		tc := tt
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			ctx := context.Background()
+			now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+			c := NewLRU(tc.capacity)
+			c.now = func() time.Time { return now }
+
+			tc.run(ctx, c, func(d time.Duration) { now = now.Add(d) })
+			for _, w := range tc.expected {
+				value, found := c.Get(ctx, w.key)
+				assert.Equal(t, w.found, found, w.key)
+				assert.Equal(t, w.value, value, w.key)
+			}
+		})
+	}
+}
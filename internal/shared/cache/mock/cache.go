@@ -0,0 +1,81 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/ming-0x0/hexago/internal/shared/cache (interfaces: CacheInterface)
+//
+// Generated by this command:
+//
+//	mockgen -destination mock/cache.go -package mock github.com/ming-0x0/hexago/internal/shared/cache CacheInterface
+//
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockCacheInterface is a mock of CacheInterface interface.
+type MockCacheInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockCacheInterfaceMockRecorder
+	isgomock struct{}
+}
+
+// MockCacheInterfaceMockRecorder is the mock recorder for MockCacheInterface.
+type MockCacheInterfaceMockRecorder struct {
+	mock *MockCacheInterface
+}
+
+// NewMockCacheInterface creates a new mock instance.
+func NewMockCacheInterface(ctrl *gomock.Controller) *MockCacheInterface {
+	mock := &MockCacheInterface{ctrl: ctrl}
+	mock.recorder = &MockCacheInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCacheInterface) EXPECT() *MockCacheInterfaceMockRecorder {
+	return m.recorder
+}
+
+// DeletePrefix mocks base method.
+func (m *MockCacheInterface) DeletePrefix(ctx context.Context, prefix string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "DeletePrefix", ctx, prefix)
+}
+
+// DeletePrefix indicates an expected call of DeletePrefix.
+func (mr *MockCacheInterfaceMockRecorder) DeletePrefix(ctx, prefix any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeletePrefix", reflect.TypeOf((*MockCacheInterface)(nil).DeletePrefix), ctx, prefix)
+}
+
+// Get mocks base method.
+func (m *MockCacheInterface) Get(ctx context.Context, key string) (any, bool) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, key)
+	ret0, _ := ret[0].(any)
+	ret1, _ := ret[1].(bool)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockCacheInterfaceMockRecorder) Get(ctx, key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockCacheInterface)(nil).Get), ctx, key)
+}
+
+// Set mocks base method.
+func (m *MockCacheInterface) Set(ctx context.Context, key string, value any, ttl time.Duration) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Set", ctx, key, value, ttl)
+}
+
+// Set indicates an expected call of Set.
+func (mr *MockCacheInterfaceMockRecorder) Set(ctx, key, value, ttl any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Set", reflect.TypeOf((*MockCacheInterface)(nil).Set), ctx, key, value, ttl)
+}
@@ -6,7 +6,7 @@ import (
 	"github.com/ming-0x0/hexago/internal/shared/errors"
 )
 
-//go:generate sh -c "$(go list -m -f '{{.Dir}}')/bin/accessor -type=Email"
+//accessor:generate -equal -text
 type Email struct {
 	value string
 }
@@ -1,4 +1,4 @@
-// Code generated by "accessor -type=Email"; DO NOT EDIT.
+// Code generated by "accessor -type=Email -equal -text"; DO NOT EDIT.
 
 package email
 
@@ -6,3 +6,23 @@ package email
 func (t Email) Value() string {
 	return t.value
 }
+
+// Equal return whether t and other are equal
+func (t Email) Equal(other Email) bool {
+	return t.value == other.value
+}
+
+// MarshalText implement encoding.TextMarshaler
+func (t Email) MarshalText() ([]byte, error) {
+	return []byte(t.value), nil
+}
+
+// UnmarshalText implement encoding.TextUnmarshaler
+func (t *Email) UnmarshalText(text []byte) error {
+	u := Email{value: string(text)}
+	if err := u.validate(); err != nil {
+		return err
+	}
+	*t = u
+	return nil
+}
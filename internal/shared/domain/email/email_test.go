@@ -42,3 +42,39 @@ func TestNew(t *testing.T) {
 		})
 	}
 }
+
+func TestEmail_Equal(t *testing.T) {
+	t.Parallel()
+
+	type args struct {
+		value string
+		other string
+	}
+
+	tests := []struct {
+		name string
+		args args
+		want bool
+	}{
+		{
+			name: "Equal",
+			args: args{value: "test@example.com", other: "test@example.com"},
+			want: true,
+		},
+		{
+			name: "Not_Equal",
+			args: args{value: "test@example.com", other: "other@example.com"},
+			want: false,
+		},
+	}
+
+	for _, tt := range tests {
+		tc := tt
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			e, _ := New(tc.args.value)
+			other, _ := New(tc.args.other)
+			assert.Equal(t, tc.want, e.Equal(*other))
+		})
+	}
+}
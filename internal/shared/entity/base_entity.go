@@ -6,6 +6,15 @@ import (
 	"gorm.io/gorm"
 )
 
+const (
+	CreatedByColumn = "created_by"
+	CreatedAtColumn = "created_at"
+	UpdatedByColumn = "updated_by"
+	UpdatedAtColumn = "updated_at"
+	DeletedByColumn = "deleted_by"
+	DeletedAtColumn = "deleted_at"
+)
+
 type BaseEntity struct {
 	CreatedBy string    `gorm:"column:created_by;not null;type:char(26)"`
 	CreatedAt time.Time `gorm:"column:created_at;not null;type:datetime;default:current_timestamp"`
@@ -13,8 +22,52 @@ type BaseEntity struct {
 	UpdatedAt time.Time `gorm:"column:updated_at;not null;type:datetime;default:current_timestamp"`
 }
 
+func (e *BaseEntity) SetCreated(by string, at time.Time) {
+	e.CreatedBy = by
+	e.CreatedAt = at
+}
+
+func (e *BaseEntity) SetUpdated(by string, at time.Time) {
+	e.UpdatedBy = by
+	e.UpdatedAt = at
+}
+
+// AuditableInterface is implemented by the entities embedding BaseEntity,
+// whose audit columns are stamped by the repository.
+type AuditableInterface interface {
+	SetCreated(by string, at time.Time)
+	SetUpdated(by string, at time.Time)
+}
+
 type BaseEntityWithDeleted struct {
 	BaseEntity
 	DeletedBy string         `gorm:"column:deleted_by;type:char(26)"`
 	DeletedAt gorm.DeletedAt `gorm:"column:deleted_at;type:datetime;index"`
 }
+
+const VersionColumn = "version"
+
+// Versioned enables optimistic locking of an entity when embedded: the
+// repository only saves it if the stored version is still the loaded one.
+type Versioned struct {
+	Version int64 `gorm:"column:version;not null;default:1"`
+}
+
+func (v *Versioned) CurrentVersion() int64 {
+	return v.Version
+}
+
+func (v *Versioned) SetVersion(version int64) {
+	v.Version = version
+}
+
+type VersionedInterface interface {
+	CurrentVersion() int64
+	SetVersion(version int64)
+}
+
+// SortableInterface is implemented by the entities whose pages can be sorted
+// on request, by the returned columns only.
+type SortableInterface interface {
+	SortableColumns() []string
+}
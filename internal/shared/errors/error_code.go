@@ -15,5 +15,6 @@ const (
 	Forbidden
 	NotFound
 	AlreadyExist
+	Conflict
 	// module specific 1001 -> 2000
 )
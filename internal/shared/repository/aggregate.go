@@ -0,0 +1,227 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	sharedErrors "github.com/ming-0x0/hexago/internal/shared/errors"
+	"github.com/ming-0x0/hexago/internal/shared/specification"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Count returns the number of rows matching spec.
+func (r *Repository[A, D, E]) Count(
+	ctx context.Context,
+	spec specification.Specification,
+	scopes ...func(*gorm.DB) *gorm.DB,
+) (int64, error) {
+	where, _, err := r.conditions(spec)
+	if err != nil {
+		return 0, err
+	}
+
+	var count int64
+	err = r.Reader(ctx).Model(new(E)).Scopes(scopes...).Scopes(where).Count(&count).Error
+	if err != nil {
+		return 0, sharedErrors.NewDomainError(sharedErrors.System, err.Error())
+	}
+
+	return count, nil
+}
+
+// Exists reports whether a row matches spec, without counting them all.
+func (r *Repository[A, D, E]) Exists(
+	ctx context.Context,
+	spec specification.Specification,
+	scopes ...func(*gorm.DB) *gorm.DB,
+) (bool, error) {
+	where, _, err := r.conditions(spec)
+	if err != nil {
+		return false, err
+	}
+
+	var found []int
+	err = r.Reader(ctx).Model(new(E)).Scopes(scopes...).Scopes(where).Select("1").Limit(1).Find(&found).Error
+	if err != nil {
+		return false, sharedErrors.NewDomainError(sharedErrors.System, err.Error())
+	}
+
+	return len(found) > 0, nil
+}
+
+// GroupCount returns the number of rows matching spec per value of column.
+// The rows whose column is NULL are counted under the empty string.
+func (r *Repository[A, D, E]) GroupCount(
+	ctx context.Context,
+	column string,
+	spec specification.Specification,
+	scopes ...func(*gorm.DB) *gorm.DB,
+) (map[string]int64, error) {
+	where, _, err := r.conditions(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	s, err := r.schema()
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := s.FieldsByDBName[column]; !ok {
+		return nil, sharedErrors.NewDomainError(sharedErrors.Validation, fmt.Sprintf("unknown group column %q of table %s", column, s.Table))
+	}
+
+	var groups []struct {
+		Value sql.NullString
+		Count int64
+	}
+	c := clause.Column{Table: clause.CurrentTable, Name: column}
+	err = r.Reader(ctx).
+		Model(new(E)).
+		Scopes(scopes...).
+		Scopes(where).
+		Select("? AS value, COUNT(*) AS count", c).
+		Clauses(clause.GroupBy{Columns: []clause.Column{c}}).
+		Scan(&groups).Error
+	if err != nil {
+		return nil, sharedErrors.NewDomainError(sharedErrors.System, err.Error())
+	}
+
+	counts := make(map[string]int64, len(groups))
+	for _, group := range groups {
+		counts[group.Value.String] += group.Count
+	}
+
+	return counts, nil
+}
+
+// Project finds the rows matching spec into dest, a pointer to a slice of
+// structs whose fields select the columns of the entity to load.
+func (r *Repository[A, D, E]) Project(
+	ctx context.Context,
+	dest any,
+	spec specification.Specification,
+	scopes ...func(*gorm.DB) *gorm.DB,
+) error {
+	where, order, err := r.conditions(spec)
+	if err != nil {
+		return err
+	}
+
+	if err := r.checkProjection(dest); err != nil {
+		return err
+	}
+
+	// gorm selects the columns of dest as it is not a slice of the entity.
+	err = r.Reader(ctx).Model(new(E)).Scopes(scopes...).Scopes(where, order).Find(dest).Error
+	if err != nil {
+		return sharedErrors.NewDomainError(sharedErrors.System, err.Error())
+	}
+
+	return nil
+}
+
+// ProjectWithPagination finds the rows of page matching spec into dest as
+// Project does, ordered as by FindByConditionsWithPagination, and returns the
+// total number of matching rows. It selects the columns of a page without
+// building domains from partly loaded entities.
+func (r *Repository[A, D, E]) ProjectWithPagination(
+	ctx context.Context,
+	dest any,
+	page Page,
+	spec specification.Specification,
+	scopes ...func(*gorm.DB) *gorm.DB,
+) (int64, error) {
+	if err := r.checkProjection(dest); err != nil {
+		return 0, err
+	}
+
+	query, count, _, err := r.paginate(ctx, page, spec, scopes)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := query.Find(dest).Error; err != nil {
+		return 0, sharedErrors.NewDomainError(sharedErrors.System, err.Error())
+	}
+
+	return count, nil
+}
+
+// checkProjection checks that the fields of dest are columns of the entity.
+func (r *Repository[A, D, E]) checkProjection(dest any) error {
+	s, err := r.schema()
+	if err != nil {
+		return err
+	}
+
+	stmt := &gorm.Statement{DB: r.db}
+	if err := stmt.Parse(dest); err != nil {
+		return sharedErrors.NewDomainError(sharedErrors.Validation, fmt.Sprintf("invalid projection: %s", err))
+	}
+	for _, column := range stmt.Schema.DBNames {
+		if _, ok := s.FieldsByDBName[column]; !ok {
+			return sharedErrors.NewDomainError(sharedErrors.Validation, fmt.Sprintf("unknown projection column %q of table %s", column, s.Table))
+		}
+	}
+
+	return nil
+}
+
+// Projector is implemented by the repositories, whose Project is typed by
+// Projection.
+type Projector interface {
+	Project(
+		ctx context.Context,
+		dest any,
+		spec specification.Specification,
+		scopes ...func(*gorm.DB) *gorm.DB,
+	) error
+}
+
+// Projection returns the rows of repo matching spec as values of T, whose
+// fields select the columns to load.
+func Projection[T any](
+	ctx context.Context,
+	repo Projector,
+	spec specification.Specification,
+	scopes ...func(*gorm.DB) *gorm.DB,
+) ([]T, error) {
+	var rows []T
+	if err := repo.Project(ctx, &rows, spec, scopes...); err != nil {
+		return nil, err
+	}
+
+	return rows, nil
+}
+
+// PageProjector is implemented by the repositories, whose
+// ProjectWithPagination is typed by PageProjection.
+type PageProjector interface {
+	ProjectWithPagination(
+		ctx context.Context,
+		dest any,
+		page Page,
+		spec specification.Specification,
+		scopes ...func(*gorm.DB) *gorm.DB,
+	) (int64, error)
+}
+
+// PageProjection returns the page of rows of repo matching spec as values of
+// T, whose fields select the columns to load.
+func PageProjection[T any](
+	ctx context.Context,
+	repo PageProjector,
+	page Page,
+	spec specification.Specification,
+	scopes ...func(*gorm.DB) *gorm.DB,
+) (PageResult[T], error) {
+	var rows []*T
+	total, err := repo.ProjectWithPagination(ctx, &rows, page, spec, scopes...)
+	if err != nil {
+		return PageResult[T]{}, err
+	}
+
+	return NewPageResult(rows, total, page), nil
+}
@@ -0,0 +1,241 @@
+package repository
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/ming-0x0/hexago/internal/shared/specification"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestRepository_Count(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		spec      specification.Specification
+		setupMock func(sqlmock.Sqlmock)
+		assertion assert.ErrorAssertionFunc
+		expected  int64
+	}{
+		{
+			name: "Success",
+			spec: specification.Eq("name", "Test"),
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta(
+					"SELECT count(*) FROM `dummy_entities` WHERE `dummy_entities`.`name` = ?",
+				)).WithArgs("Test").WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+			},
+			assertion: assert.NoError,
+			expected:  3,
+		},
+		{
+			name:      "Failure_UnknownColumn",
+			spec:      specification.Eq("nmae", "Test"),
+			setupMock: func(mock sqlmock.Sqlmock) {},
+			assertion: assert.Error,
+		},
+		{
+			name: "Failure_DBError",
+			spec: nil,
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SELECT count").WillReturnError(gorm.ErrInvalidField)
+			},
+			assertion: assert.Error,
+		},
+	}
+
+	for _, tt := range tests {
+		tc := tt
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			repo, mockedDB, _, sqlMock, _, _ := setupTest(t, DummyAdapter{})
+			defer teardownTest(mockedDB)
+
+			tc.setupMock(sqlMock)
+			got, err := repo.Count(context.Background(), tc.spec)
+			tc.assertion(t, err)
+			assert.Equal(t, tc.expected, got)
+			assert.NoError(t, sqlMock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestRepository_Exists(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		setupMock func(sqlmock.Sqlmock)
+		assertion assert.ErrorAssertionFunc
+		expected  bool
+	}{
+		{
+			name: "Success_Found",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta(
+					"SELECT 1 FROM `dummy_entities` WHERE `dummy_entities`.`id` = ? LIMIT ?",
+				)).WithArgs(1, 1).WillReturnRows(sqlmock.NewRows([]string{"1"}).AddRow(1))
+			},
+			assertion: assert.NoError,
+			expected:  true,
+		},
+		{
+			name: "Success_NotFound",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta(
+					"SELECT 1 FROM `dummy_entities` WHERE `dummy_entities`.`id` = ? LIMIT ?",
+				)).WithArgs(1, 1).WillReturnRows(sqlmock.NewRows([]string{"1"}))
+			},
+			assertion: assert.NoError,
+			expected:  false,
+		},
+		{
+			name: "Failure_DBError",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SELECT 1").WillReturnError(gorm.ErrInvalidField)
+			},
+			assertion: assert.Error,
+		},
+	}
+
+	for _, tt := range tests {
+		tc := tt
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			repo, mockedDB, _, sqlMock, _, _ := setupTest(t, DummyAdapter{})
+			defer teardownTest(mockedDB)
+
+			tc.setupMock(sqlMock)
+			got, err := repo.Exists(context.Background(), specification.Eq("id", 1))
+			tc.assertion(t, err)
+			assert.Equal(t, tc.expected, got)
+			assert.NoError(t, sqlMock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestRepository_GroupCount(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		column    string
+		setupMock func(sqlmock.Sqlmock)
+		assertion assert.ErrorAssertionFunc
+		expected  map[string]int64
+	}{
+		{
+			name:   "Success",
+			column: "name",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				rows := sqlmock.NewRows([]string{"value", "count"}).
+					AddRow("A", 2).
+					AddRow("B", 1).
+					AddRow(nil, 4)
+				mock.ExpectQuery(regexp.QuoteMeta(
+					"SELECT `dummy_entities`.`name` AS value, COUNT(*) AS count FROM `dummy_entities` WHERE `dummy_entities`.`id` > ? GROUP BY `dummy_entities`.`name`",
+				)).WithArgs(0).WillReturnRows(rows)
+			},
+			assertion: assert.NoError,
+			expected:  map[string]int64{"A": 2, "B": 1, "": 4},
+		},
+		{
+			name:      "Failure_UnknownColumn",
+			column:    "status",
+			setupMock: func(mock sqlmock.Sqlmock) {},
+			assertion: assert.Error,
+		},
+		{
+			name:   "Failure_DBError",
+			column: "name",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SELECT (.+) GROUP BY").WillReturnError(gorm.ErrInvalidField)
+			},
+			assertion: assert.Error,
+		},
+	}
+
+	for _, tt := range tests {
+		tc := tt
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			repo, mockedDB, _, sqlMock, _, _ := setupTest(t, DummyAdapter{})
+			defer teardownTest(mockedDB)
+
+			tc.setupMock(sqlMock)
+			got, err := repo.GroupCount(context.Background(), tc.column, specification.Gt("id", 0))
+			tc.assertion(t, err)
+			assert.Equal(t, tc.expected, got)
+			assert.NoError(t, sqlMock.ExpectationsWereMet())
+		})
+	}
+}
+
+type DummyName struct {
+	Name string
+}
+
+type DummyID struct {
+	ID int
+}
+
+type DummyUnknown struct {
+	Status int
+}
+
+func TestProjection(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Success", func(t *testing.T) {
+		t.Parallel()
+		repo, mockedDB, _, sqlMock, _, _ := setupTest(t, DummyAdapter{})
+		defer teardownTest(mockedDB)
+
+		sqlMock.ExpectQuery(regexp.QuoteMeta(
+			"SELECT `dummy_entities`.`name` FROM `dummy_entities` WHERE `dummy_entities`.`id` > ? ORDER BY `dummy_entities`.`name`",
+		)).WithArgs(0).WillReturnRows(sqlmock.NewRows([]string{"name"}).AddRow("A").AddRow("B"))
+
+		spec := specification.OrderBy(specification.Gt("id", 0), specification.Asc("name"))
+		got, err := Projection[DummyName](context.Background(), repo, spec)
+		assert.NoError(t, err)
+		assert.Equal(t, []DummyName{{Name: "A"}, {Name: "B"}}, got)
+		assert.NoError(t, sqlMock.ExpectationsWereMet())
+	})
+
+	t.Run("Failure_UnknownColumn", func(t *testing.T) {
+		t.Parallel()
+		repo, mockedDB, _, sqlMock, _, _ := setupTest(t, DummyAdapter{})
+		defer teardownTest(mockedDB)
+
+		got, err := Projection[DummyUnknown](context.Background(), repo, nil)
+		assert.Error(t, err)
+		assert.Nil(t, got)
+		assert.NoError(t, sqlMock.ExpectationsWereMet())
+	})
+
+	t.Run("Failure_NotStruct", func(t *testing.T) {
+		t.Parallel()
+		repo, mockedDB, _, sqlMock, _, _ := setupTest(t, DummyAdapter{})
+		defer teardownTest(mockedDB)
+
+		got, err := Projection[string](context.Background(), repo, nil)
+		assert.Error(t, err)
+		assert.Nil(t, got)
+		assert.NoError(t, sqlMock.ExpectationsWereMet())
+	})
+
+	t.Run("Failure_DBError", func(t *testing.T) {
+		t.Parallel()
+		repo, mockedDB, _, sqlMock, _, _ := setupTest(t, DummyAdapter{})
+		defer teardownTest(mockedDB)
+
+		sqlMock.ExpectQuery("SELECT (.+) FROM `dummy_entities`").WillReturnError(gorm.ErrInvalidField)
+
+		_, err := Projection[DummyName](context.Background(), repo, nil)
+		assert.Error(t, err)
+	})
+}
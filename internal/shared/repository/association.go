@@ -0,0 +1,217 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"slices"
+
+	sharedEntity "github.com/ming-0x0/hexago/internal/shared/entity"
+	sharedErrors "github.com/ming-0x0/hexago/internal/shared/errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// relationships returns the relationships of the associations of the repository.
+func (r *Repository[A, D, E]) relationships() ([]*schema.Relationship, error) {
+	if len(r.options.associations) == 0 {
+		return nil, nil
+	}
+
+	s, err := r.schema()
+	if err != nil {
+		return nil, err
+	}
+
+	relationships := make([]*schema.Relationship, 0, len(r.options.associations))
+	for _, name := range r.options.associations {
+		rel, ok := s.Relationships.Relations[name]
+		if !ok || (rel.Type != schema.HasOne && rel.Type != schema.HasMany) {
+			return nil, sharedErrors.NewDomainError(sharedErrors.System, fmt.Sprintf("unknown has one or has many association %q of table %s", name, s.Table))
+		}
+		if rel.FieldSchema.PrioritizedPrimaryField == nil {
+			return nil, sharedErrors.NewDomainError(sharedErrors.System, fmt.Sprintf("association %q of table %s has no primary key", name, s.Table))
+		}
+		relationships = append(relationships, rel)
+	}
+
+	return relationships, nil
+}
+
+// preload returns the scope loading the associations of the repository.
+func (r *Repository[A, D, E]) preload() (func(*gorm.DB) *gorm.DB, error) {
+	relationships, err := r.relationships()
+	if err != nil {
+		return nil, err
+	}
+
+	return func(db *gorm.DB) *gorm.DB {
+		for _, rel := range relationships {
+			db = db.Preload(rel.Name)
+		}
+		return db
+	}, nil
+}
+
+// omitAssociations is a scope leaving the associations of the repository to
+// saveAssociations.
+func (r *Repository[A, D, E]) omitAssociations(db *gorm.DB) *gorm.DB {
+	if len(r.options.associations) == 0 {
+		return db
+	}
+	return db.Omit(clause.Associations)
+}
+
+// saveAssociations upserts the children of entity after it is written, then
+// deletes the stored children it no longer has, unless it was just created.
+// Soft deletable children are soft deleted.
+func (r *Repository[A, D, E]) saveAssociations(ctx context.Context, entity *E, created bool) error {
+	relationships, err := r.relationships()
+	if err != nil {
+		return err
+	}
+
+	for _, rel := range relationships {
+		if err := r.saveAssociation(ctx, rel, reflect.ValueOf(entity), created); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *Repository[A, D, E]) saveAssociation(ctx context.Context, rel *schema.Relationship, parent reflect.Value, created bool) error {
+	children := reflect.MakeSlice(reflect.SliceOf(reflect.PointerTo(rel.FieldSchema.ModelType)), 0, 0)
+	switch field := reflect.Indirect(rel.Field.ReflectValueOf(ctx, parent)); field.Kind() {
+	case reflect.Slice:
+		for i := range field.Len() {
+			child := field.Index(i)
+			if child.Kind() != reflect.Pointer {
+				child = child.Addr()
+			} else if child.IsNil() {
+				continue
+			}
+			children = reflect.Append(children, child)
+		}
+	case reflect.Struct:
+		// a has one struct left zero is no child, not an empty one.
+		if !field.IsZero() {
+			children = reflect.Append(children, field.Addr())
+		}
+	}
+
+	// the foreign keys of the children reference the parent.
+	conditions := make([]clause.Expression, 0, len(rel.References))
+	for _, ref := range rel.References {
+		value := any(ref.PrimaryValue)
+		if ref.OwnPrimaryKey {
+			value, _ = ref.PrimaryKey.ValueOf(ctx, parent)
+		}
+		for i := range children.Len() {
+			if err := ref.ForeignKey.Set(ctx, children.Index(i), value); err != nil {
+				return sharedErrors.NewDomainError(sharedErrors.System, err.Error())
+			}
+		}
+		conditions = append(conditions, clause.Eq{
+			Column: clause.Column{Table: clause.CurrentTable, Name: ref.ForeignKey.DBName},
+			Value:  value,
+		})
+	}
+
+	pk := rel.FieldSchema.PrioritizedPrimaryField
+	if err := r.checkOwnership(ctx, rel, children, conditions); err != nil {
+		return err
+	}
+
+	kept := make([]any, 0, children.Len())
+	if children.Len() > 0 {
+		// the foreign keys are not updated, so that a child never moves to another parent.
+		columns := slices.DeleteFunc(updateColumns(rel.FieldSchema), func(column string) bool {
+			return slices.ContainsFunc(rel.References, func(ref *schema.Reference) bool {
+				return ref.ForeignKey.DBName == column
+			})
+		})
+		onConflict := clause.OnConflict{DoUpdates: clause.AssignmentColumns(columns)}
+		if _, ok := reflect.New(rel.FieldSchema.ModelType).Interface().(sharedEntity.AuditableInterface); ok {
+			if err := stampChildren(ctx, r.db.NowFunc(), children); err != nil {
+				return err
+			}
+		}
+
+		dest := reflect.New(children.Type())
+		dest.Elem().Set(children)
+		err := r.DB(ctx).Omit(clause.Associations).Clauses(onConflict).Create(dest.Interface()).Error
+		if err != nil {
+			return sharedErrors.NewDomainError(sharedErrors.System, err.Error())
+		}
+
+		for i := range children.Len() {
+			id, _ := pk.ValueOf(ctx, children.Index(i))
+			kept = append(kept, id)
+		}
+	}
+
+	if created {
+		return nil
+	}
+
+	if len(kept) > 0 {
+		conditions = append(conditions, clause.Not(clause.IN{
+			Column: clause.Column{Table: clause.CurrentTable, Name: pk.DBName},
+			Values: kept,
+		}))
+	}
+	where := func(db *gorm.DB) *gorm.DB {
+		return db.Where(clause.And(conditions...))
+	}
+	if isSoftDeleted(rel.FieldSchema) {
+		return r.softDelete(ctx, rel.FieldSchema, where)
+	}
+
+	model := reflect.New(rel.FieldSchema.ModelType).Interface()
+	if err := r.DB(ctx).Scopes(where).Delete(model).Error; err != nil {
+		return sharedErrors.NewDomainError(sharedErrors.System, err.Error())
+	}
+
+	return nil
+}
+
+// checkOwnership rejects the stored children of an association whose foreign
+// keys, selected by conditions, reference another parent.
+func (r *Repository[A, D, E]) checkOwnership(
+	ctx context.Context,
+	rel *schema.Relationship,
+	children reflect.Value,
+	conditions []clause.Expression,
+) error {
+	pk := rel.FieldSchema.PrioritizedPrimaryField
+	ids := make([]any, 0, children.Len())
+	for i := range children.Len() {
+		if id, zero := pk.ValueOf(ctx, children.Index(i)); !zero {
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	var count int64
+	err := r.DB(ctx).
+		Model(reflect.New(rel.FieldSchema.ModelType).Interface()).
+		Unscoped().
+		Where(clause.IN{Column: clause.Column{Table: clause.CurrentTable, Name: pk.DBName}, Values: ids}).
+		Where(clause.Not(conditions...)).
+		Count(&count).Error
+	if err != nil {
+		return sharedErrors.NewDomainError(sharedErrors.System, err.Error())
+	}
+	if count > 0 {
+		return sharedErrors.NewDomainError(
+			sharedErrors.BadRequest,
+			fmt.Sprintf("%d children of association %q belong to another parent", count, rel.Name),
+		)
+	}
+
+	return nil
+}
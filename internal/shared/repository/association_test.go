@@ -0,0 +1,419 @@
+package repository
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/ming-0x0/hexago/internal/shared/actor"
+	"github.com/ming-0x0/hexago/internal/shared/dbmocker"
+	"github.com/ming-0x0/hexago/internal/shared/specification"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+type DummyChildEntity struct {
+	ID       int
+	ParentID int
+	Name     string
+}
+
+type DummyParentEntity struct {
+	ID       int
+	Name     string
+	Children []DummyChildEntity `gorm:"foreignKey:ParentID"`
+}
+
+type DummyParentDomain struct {
+	ID       int
+	Name     string
+	Children []string
+}
+
+type DummyParentAdapter struct{}
+
+func (a *DummyParentAdapter) ToDomain(entity *DummyParentEntity) (*DummyParentDomain, error) {
+	domain := &DummyParentDomain{ID: entity.ID, Name: entity.Name}
+	for _, child := range entity.Children {
+		domain.Children = append(domain.Children, child.Name)
+	}
+	return domain, nil
+}
+
+// ToEntity keys the children by name, as a real adapter would keep their IDs.
+func (a *DummyParentAdapter) ToEntity(domain *DummyParentDomain) (*DummyParentEntity, error) {
+	entity := &DummyParentEntity{ID: domain.ID, Name: domain.Name}
+	for _, name := range domain.Children {
+		child := DummyChildEntity{Name: name}
+		if name == "Kept" {
+			child.ID = 10
+		}
+		entity.Children = append(entity.Children, child)
+	}
+	return entity, nil
+}
+
+func (a *DummyParentAdapter) ToDomains(entities []*DummyParentEntity) ([]*DummyParentDomain, error) {
+	domains := make([]*DummyParentDomain, 0, len(entities))
+	for _, e := range entities {
+		d, _ := a.ToDomain(e)
+		domains = append(domains, d)
+	}
+	return domains, nil
+}
+
+func (a *DummyParentAdapter) ToEntities(domains []*DummyParentDomain) ([]*DummyParentEntity, error) {
+	entities := make([]*DummyParentEntity, 0, len(domains))
+	for _, d := range domains {
+		e, _ := a.ToEntity(d)
+		entities = append(entities, e)
+	}
+	return entities, nil
+}
+
+func setupAssociationTest(t *testing.T, associations ...string) (*Repository[*DummyParentAdapter, DummyParentDomain, DummyParentEntity], *dbmocker.MockedRepository, sqlmock.Sqlmock) {
+	mockedDB, err := dbmocker.NewMockedDB()
+	if err != nil {
+		t.Fatalf("error when creating mock DB: %v", err)
+	}
+	mockedDB.SqlMock.MatchExpectationsInOrder(true)
+
+	repo := NewRepository(mockedDB.GormDB, mockedDB.Logger, &DummyParentAdapter{}, WithAssociations(associations...))
+	return repo, mockedDB, mockedDB.SqlMock
+}
+
+func TestRepository_Associations(t *testing.T) {
+	t.Parallel()
+
+	type repo = *Repository[*DummyParentAdapter, DummyParentDomain, DummyParentEntity]
+
+	tests := []struct {
+		name         string
+		associations []string
+		call         func(context.Context, repo) (any, error)
+		setupMock    func(sqlmock.Sqlmock)
+		assertion    assert.ErrorAssertionFunc
+		expected     any
+	}{
+		{
+			name:         "Find_Preloads",
+			associations: []string{"Children"},
+			call: func(ctx context.Context, r repo) (any, error) {
+				return r.FindByConditions(ctx, specification.Eq("id", 1))
+			},
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta(
+					"SELECT * FROM `dummy_parent_entities` WHERE `dummy_parent_entities`.`id` = ?",
+				)).WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "Parent"))
+				mock.ExpectQuery(regexp.QuoteMeta(
+					"SELECT * FROM `dummy_child_entities` WHERE `dummy_child_entities`.`parent_id` = ?",
+				)).WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"id", "parent_id", "name"}).AddRow(10, 1, "A").AddRow(11, 1, "B"))
+			},
+			assertion: assert.NoError,
+			expected:  []*DummyParentDomain{{ID: 1, Name: "Parent", Children: []string{"A", "B"}}},
+		},
+		{
+			name:         "Create_WithChildren",
+			associations: []string{"Children"},
+			call: func(ctx context.Context, r repo) (any, error) {
+				domain := &DummyParentDomain{Name: "Parent", Children: []string{"A"}}
+				return domain, r.Create(ctx, domain)
+			},
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(regexp.QuoteMeta(
+					"INSERT INTO `dummy_parent_entities` (`name`) VALUES (?)",
+				)).WithArgs("Parent").WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec(regexp.QuoteMeta(
+					"INSERT INTO `dummy_child_entities` (`parent_id`,`name`) VALUES (?,?) ON DUPLICATE KEY UPDATE `name`=VALUES(`name`)",
+				)).WithArgs(1, "A").WillReturnResult(sqlmock.NewResult(20, 1))
+				mock.ExpectCommit()
+			},
+			assertion: assert.NoError,
+			expected:  &DummyParentDomain{ID: 1, Name: "Parent", Children: []string{"A"}},
+		},
+		{
+			name:         "Save_DiffsChildren",
+			associations: []string{"Children"},
+			call: func(ctx context.Context, r repo) (any, error) {
+				return nil, r.Save(ctx, &DummyParentDomain{ID: 1, Name: "Parent", Children: []string{"Kept", "New"}})
+			},
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(regexp.QuoteMeta(
+					"UPDATE `dummy_parent_entities` SET `name`=? WHERE `id` = ?",
+				)).WithArgs("Parent", 1).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectQuery(regexp.QuoteMeta(
+					"SELECT count(*) FROM `dummy_child_entities` WHERE `dummy_child_entities`.`id` = ? AND `dummy_child_entities`.`parent_id` <> ?",
+				)).WithArgs(10, 1).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+				mock.ExpectExec(regexp.QuoteMeta(
+					"INSERT INTO `dummy_child_entities` (`parent_id`,`name`,`id`) VALUES (?,?,?),(?,?,DEFAULT) ON DUPLICATE KEY UPDATE `name`=VALUES(`name`)",
+				)).WithArgs(1, "Kept", 10, 1, "New").WillReturnResult(sqlmock.NewResult(20, 2))
+				mock.ExpectExec(regexp.QuoteMeta(
+					"DELETE FROM `dummy_child_entities` WHERE `dummy_child_entities`.`parent_id` = ? AND `dummy_child_entities`.`id` NOT IN (?,?)",
+				)).WithArgs(1, 10, 20).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+			assertion: assert.NoError,
+		},
+		{
+			name:         "Failure_ChildOfAnotherParent",
+			associations: []string{"Children"},
+			call: func(ctx context.Context, r repo) (any, error) {
+				return nil, r.Save(ctx, &DummyParentDomain{ID: 1, Name: "Parent", Children: []string{"Kept"}})
+			},
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec("UPDATE `dummy_parent_entities`").WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectQuery("SELECT count").WithArgs(10, 1).
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+				mock.ExpectRollback()
+			},
+			assertion: assert.Error,
+		},
+		{
+			name:         "Save_WithoutChildren",
+			associations: []string{"Children"},
+			call: func(ctx context.Context, r repo) (any, error) {
+				return nil, r.Save(ctx, &DummyParentDomain{ID: 1, Name: "Parent"})
+			},
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec("UPDATE `dummy_parent_entities`").WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(regexp.QuoteMeta(
+					"DELETE FROM `dummy_child_entities` WHERE `dummy_child_entities`.`parent_id` = ?",
+				)).WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 2))
+				mock.ExpectCommit()
+			},
+			assertion: assert.NoError,
+		},
+		{
+			name:         "Failure_UnknownAssociation",
+			associations: []string{"Parent"},
+			call: func(ctx context.Context, r repo) (any, error) {
+				return r.FindByConditions(ctx, nil)
+			},
+			setupMock: func(mock sqlmock.Sqlmock) {},
+			assertion: assert.Error,
+			expected:  []*DummyParentDomain(nil),
+		},
+	}
+
+	for _, tt := range tests {
+		tc := tt
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			repo, mockedDB, sqlMock := setupAssociationTest(t, tc.associations...)
+			defer teardownTest(mockedDB)
+
+			tc.setupMock(sqlMock)
+			got, err := tc.call(context.Background(), repo)
+			tc.assertion(t, err)
+			assert.Equal(t, tc.expected, got)
+			assert.NoError(t, sqlMock.ExpectationsWereMet())
+		})
+	}
+}
+
+type DummySoftChildEntity struct {
+	ID        int
+	ParentID  int
+	Name      string
+	DeletedBy string
+	DeletedAt gorm.DeletedAt
+}
+
+type DummySoftParentEntity struct {
+	ID       int
+	Name     string
+	Children []DummySoftChildEntity `gorm:"foreignKey:ParentID"`
+}
+
+type DummySoftParentAdapter struct{}
+
+func (a *DummySoftParentAdapter) ToDomain(entity *DummySoftParentEntity) (*DummyParentDomain, error) {
+	domain := &DummyParentDomain{ID: entity.ID, Name: entity.Name}
+	for _, child := range entity.Children {
+		domain.Children = append(domain.Children, child.Name)
+	}
+	return domain, nil
+}
+
+func (a *DummySoftParentAdapter) ToEntity(domain *DummyParentDomain) (*DummySoftParentEntity, error) {
+	entity := &DummySoftParentEntity{ID: domain.ID, Name: domain.Name}
+	for _, name := range domain.Children {
+		entity.Children = append(entity.Children, DummySoftChildEntity{Name: name})
+	}
+	return entity, nil
+}
+
+func (a *DummySoftParentAdapter) ToDomains(entities []*DummySoftParentEntity) ([]*DummyParentDomain, error) {
+	domains := make([]*DummyParentDomain, 0, len(entities))
+	for _, e := range entities {
+		d, _ := a.ToDomain(e)
+		domains = append(domains, d)
+	}
+	return domains, nil
+}
+
+func (a *DummySoftParentAdapter) ToEntities(domains []*DummyParentDomain) ([]*DummySoftParentEntity, error) {
+	entities := make([]*DummySoftParentEntity, 0, len(domains))
+	for _, d := range domains {
+		e, _ := a.ToEntity(d)
+		entities = append(entities, e)
+	}
+	return entities, nil
+}
+
+func TestRepository_Associations_SoftDeletedChildren(t *testing.T) {
+	t.Parallel()
+
+	mockedDB, err := dbmocker.NewMockedDB()
+	if err != nil {
+		t.Fatalf("error when creating mock DB: %v", err)
+	}
+	defer teardownTest(mockedDB)
+	sqlMock := mockedDB.SqlMock
+	sqlMock.MatchExpectationsInOrder(true)
+
+	repo := NewRepository(mockedDB.GormDB, mockedDB.Logger, &DummySoftParentAdapter{}, WithAssociations("Children"))
+
+	sqlMock.ExpectBegin()
+	sqlMock.ExpectExec("UPDATE `dummy_soft_parent_entities`").WillReturnResult(sqlmock.NewResult(0, 1))
+	sqlMock.ExpectExec(regexp.QuoteMeta(
+		"UPDATE `dummy_soft_child_entities` SET `deleted_at`=?,`deleted_by`=? WHERE `dummy_soft_child_entities`.`parent_id` = ? AND `dummy_soft_child_entities`.`deleted_at` IS NULL",
+	)).WithArgs(sqlmock.AnyArg(), "01ARZ3NDEKTSV4RRFFQ69G5FAV", 1).WillReturnResult(sqlmock.NewResult(0, 2))
+	sqlMock.ExpectCommit()
+
+	ctx := actor.WithID(context.Background(), "01ARZ3NDEKTSV4RRFFQ69G5FAV")
+	err = repo.Save(ctx, &DummyParentDomain{ID: 1, Name: "Parent"})
+	assert.NoError(t, err)
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+type DummyProfileEntity struct {
+	ID       int
+	HolderID int
+	Bio      string
+}
+
+type DummyHolderEntity struct {
+	ID      int
+	Name    string
+	Profile DummyProfileEntity `gorm:"foreignKey:HolderID"`
+}
+
+type DummyHolderDomain struct {
+	ID   int
+	Name string
+	Bio  string
+}
+
+type DummyHolderAdapter struct{}
+
+func (a *DummyHolderAdapter) ToDomain(entity *DummyHolderEntity) (*DummyHolderDomain, error) {
+	return &DummyHolderDomain{ID: entity.ID, Name: entity.Name, Bio: entity.Profile.Bio}, nil
+}
+
+// ToEntity leaves the profile zero when the domain has no bio.
+func (a *DummyHolderAdapter) ToEntity(domain *DummyHolderDomain) (*DummyHolderEntity, error) {
+	entity := &DummyHolderEntity{ID: domain.ID, Name: domain.Name}
+	if domain.Bio != "" {
+		entity.Profile = DummyProfileEntity{Bio: domain.Bio}
+	}
+	return entity, nil
+}
+
+func (a *DummyHolderAdapter) ToDomains(entities []*DummyHolderEntity) ([]*DummyHolderDomain, error) {
+	domains := make([]*DummyHolderDomain, 0, len(entities))
+	for _, e := range entities {
+		d, _ := a.ToDomain(e)
+		domains = append(domains, d)
+	}
+	return domains, nil
+}
+
+func (a *DummyHolderAdapter) ToEntities(domains []*DummyHolderDomain) ([]*DummyHolderEntity, error) {
+	entities := make([]*DummyHolderEntity, 0, len(domains))
+	for _, d := range domains {
+		e, _ := a.ToEntity(d)
+		entities = append(entities, e)
+	}
+	return entities, nil
+}
+
+func TestRepository_Associations_HasOne(t *testing.T) {
+	t.Parallel()
+
+	type repo = *Repository[*DummyHolderAdapter, DummyHolderDomain, DummyHolderEntity]
+
+	tests := []struct {
+		name      string
+		call      func(context.Context, repo) error
+		setupMock func(sqlmock.Sqlmock)
+	}{
+		{
+			name: "Create_WithChild",
+			call: func(ctx context.Context, r repo) error {
+				return r.Create(ctx, &DummyHolderDomain{Name: "Holder", Bio: "Bio"})
+			},
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(regexp.QuoteMeta(
+					"INSERT INTO `dummy_holder_entities` (`name`) VALUES (?)",
+				)).WithArgs("Holder").WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec(regexp.QuoteMeta(
+					"INSERT INTO `dummy_profile_entities` (`holder_id`,`bio`) VALUES (?,?) ON DUPLICATE KEY UPDATE `bio`=VALUES(`bio`)",
+				)).WithArgs(1, "Bio").WillReturnResult(sqlmock.NewResult(20, 1))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "Create_WithoutChild",
+			call: func(ctx context.Context, r repo) error {
+				return r.Create(ctx, &DummyHolderDomain{Name: "Holder"})
+			},
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(regexp.QuoteMeta(
+					"INSERT INTO `dummy_holder_entities` (`name`) VALUES (?)",
+				)).WithArgs("Holder").WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "Save_WithoutChild",
+			call: func(ctx context.Context, r repo) error {
+				return r.Save(ctx, &DummyHolderDomain{ID: 1, Name: "Holder"})
+			},
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec("UPDATE `dummy_holder_entities`").WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(regexp.QuoteMeta(
+					"DELETE FROM `dummy_profile_entities` WHERE `dummy_profile_entities`.`holder_id` = ?",
+				)).WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tc := tt
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			mockedDB, err := dbmocker.NewMockedDB()
+			if err != nil {
+				t.Fatalf("error when creating mock DB: %v", err)
+			}
+			defer teardownTest(mockedDB)
+			sqlMock := mockedDB.SqlMock
+			sqlMock.MatchExpectationsInOrder(true)
+
+			repo := NewRepository(mockedDB.GormDB, mockedDB.Logger, &DummyHolderAdapter{}, WithAssociations("Profile"))
+			tc.setupMock(sqlMock)
+			assert.NoError(t, tc.call(context.Background(), repo))
+			assert.NoError(t, sqlMock.ExpectationsWereMet())
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"reflect"
+
+	"github.com/ming-0x0/hexago/internal/shared/audit"
+	sharedErrors "github.com/ming-0x0/hexago/internal/shared/errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// stored returns the row of entity as stored before it is saved, or nil if
+// there is none or the repository records no audit log. The created columns,
+// which Save keeps, are copied from the row to entity.
+func (r *Repository[A, D, E]) stored(ctx context.Context, entity *E) (*E, error) {
+	if r.options.recorder == nil {
+		return nil, nil
+	}
+
+	s, err := r.schema()
+	if err != nil {
+		return nil, err
+	}
+	pk := s.PrioritizedPrimaryField
+	if pk == nil {
+		return nil, nil
+	}
+	id, zero := pk.ValueOf(ctx, reflect.ValueOf(entity))
+	if zero {
+		return nil, nil
+	}
+
+	before := new(E)
+	err = r.DB(ctx).
+		Where(clause.Eq{Column: clause.Column{Table: clause.CurrentTable, Name: pk.DBName}, Value: id}).
+		Take(before).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, sharedErrors.NewDomainError(sharedErrors.System, err.Error())
+	}
+
+	if isAuditable[E]() {
+		for _, column := range createdColumns {
+			field := s.FieldsByDBName[column]
+			value, _ := field.ValueOf(ctx, reflect.ValueOf(before))
+			if err := field.Set(ctx, reflect.ValueOf(entity), value); err != nil {
+				return nil, sharedErrors.NewDomainError(sharedErrors.System, err.Error())
+			}
+		}
+	}
+
+	return before, nil
+}
+
+// record records the audit logs of action on entities, given their values
+// before and after the write; befores is nil for creations and afters for
+// deletions.
+func (r *Repository[A, D, E]) record(ctx context.Context, action audit.Action, befores, afters []*E) error {
+	if r.options.recorder == nil {
+		return nil
+	}
+
+	s, err := r.schema()
+	if err != nil {
+		return err
+	}
+
+	var logs []*audit.Log
+	for i := range max(len(befores), len(afters)) {
+		// a nil *E must be passed as an untyped nil.
+		var before, after any
+		if i < len(befores) && befores[i] != nil {
+			before = befores[i]
+		}
+		if i < len(afters) && afters[i] != nil {
+			after = afters[i]
+		}
+
+		if log := audit.NewLog(ctx, s, action, before, after); log != nil {
+			logs = append(logs, log)
+		}
+	}
+
+	if err := r.options.recorder.Record(ctx, logs...); err != nil {
+		return sharedErrors.NewDomainError(sharedErrors.System, err.Error())
+	}
+
+	return nil
+}
@@ -0,0 +1,141 @@
+package repository
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/ming-0x0/hexago/internal/shared/actor"
+	"github.com/ming-0x0/hexago/internal/shared/audit"
+	"github.com/ming-0x0/hexago/internal/shared/dbmocker"
+	"github.com/ming-0x0/hexago/internal/shared/specification"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+const insertAuditLogs = "INSERT INTO `audit_logs` (`entity_type`,`entity_id`,`action`,`actor_id`,`changes`) VALUES (?,?,?,?,?)"
+
+func setupAuditTest(t *testing.T) (*Repository[*DummyAdapter, DummyDomain, DummyEntity], *dbmocker.MockedRepository, sqlmock.Sqlmock) {
+	mockedDB, err := dbmocker.NewMockedDB()
+	if err != nil {
+		t.Fatalf("error when creating mock DB: %v", err)
+	}
+
+	repo := NewRepository(mockedDB.GormDB, mockedDB.Logger, &DummyAdapter{}, WithRecorder(audit.NewRecorder(mockedDB.GormDB)))
+	return repo, mockedDB, mockedDB.SqlMock
+}
+
+func TestRepository_Audit(t *testing.T) {
+	t.Parallel()
+
+	const actorID = "01ARZ3NDEKTSV4RRFFQ69G5FAV"
+
+	type repo = *Repository[*DummyAdapter, DummyDomain, DummyEntity]
+
+	tests := []struct {
+		name      string
+		call      func(context.Context, repo) error
+		setupMock func(sqlmock.Sqlmock)
+		assertion assert.ErrorAssertionFunc
+	}{
+		{
+			name: "Create",
+			call: func(ctx context.Context, r repo) error {
+				return r.Create(ctx, &DummyDomain{Name: "Test"})
+			},
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `dummy_entities` (`name`) VALUES (?)")).
+					WithArgs("Test").WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec(regexp.QuoteMeta(insertAuditLogs)).
+					WithArgs("dummy_entities", "1", audit.Create, actorID, `{"id":{"before":null,"after":1},"name":{"before":null,"after":"Test"}}`).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
+			},
+			assertion: assert.NoError,
+		},
+		{
+			name: "Save_ChangedColumnsOnly",
+			call: func(ctx context.Context, r repo) error {
+				return r.Save(ctx, &DummyDomain{ID: 1, Name: "New"})
+			},
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `dummy_entities` WHERE `dummy_entities`.`id` = ? LIMIT ?")).
+					WithArgs(1, 1).WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "Old"))
+				mock.ExpectExec(regexp.QuoteMeta("UPDATE `dummy_entities` SET `name`=? WHERE `id` = ?")).
+					WithArgs("New", 1).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(regexp.QuoteMeta(insertAuditLogs)).
+					WithArgs("dummy_entities", "1", audit.Update, actorID, `{"name":{"before":"Old","after":"New"}}`).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
+			},
+			assertion: assert.NoError,
+		},
+		{
+			name: "Save_Unchanged",
+			call: func(ctx context.Context, r repo) error {
+				return r.Save(ctx, &DummyDomain{ID: 1, Name: "Test"})
+			},
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `dummy_entities` WHERE `dummy_entities`.`id` = ? LIMIT ?")).
+					WithArgs(1, 1).WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "Test"))
+				mock.ExpectExec(regexp.QuoteMeta("UPDATE `dummy_entities` SET `name`=? WHERE `id` = ?")).
+					WithArgs("Test", 1).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+			assertion: assert.NoError,
+		},
+		{
+			name: "DeleteByConditions",
+			call: func(ctx context.Context, r repo) error {
+				return r.DeleteByConditions(ctx, specification.Eq("name", "Test"))
+			},
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `dummy_entities` WHERE `dummy_entities`.`name` = ?")).
+					WithArgs("Test").WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "Test").AddRow(2, "Test"))
+				mock.ExpectExec(regexp.QuoteMeta("DELETE FROM `dummy_entities` WHERE `dummy_entities`.`name` = ?")).
+					WithArgs("Test").WillReturnResult(sqlmock.NewResult(0, 2))
+				mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `audit_logs` (`entity_type`,`entity_id`,`action`,`actor_id`,`changes`) VALUES (?,?,?,?,?),(?,?,?,?,?)")).
+					WithArgs(
+						"dummy_entities", "1", audit.Delete, actorID, `{"id":{"before":1,"after":null},"name":{"before":"Test","after":null}}`,
+						"dummy_entities", "2", audit.Delete, actorID, `{"id":{"before":2,"after":null},"name":{"before":"Test","after":null}}`,
+					).
+					WillReturnResult(sqlmock.NewResult(1, 2))
+				mock.ExpectCommit()
+			},
+			assertion: assert.NoError,
+		},
+		{
+			name: "Failure_RecordError",
+			call: func(ctx context.Context, r repo) error {
+				return r.Create(ctx, &DummyDomain{Name: "Test"})
+			},
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `dummy_entities` (`name`) VALUES (?)")).
+					WithArgs("Test").WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec(regexp.QuoteMeta(insertAuditLogs)).WillReturnError(gorm.ErrInvalidField)
+				mock.ExpectRollback()
+			},
+			assertion: assert.Error,
+		},
+	}
+
+	for _, tt := range tests {
+		tc := tt
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			repo, mockedDB, sqlMock := setupAuditTest(t)
+			defer teardownTest(mockedDB)
+
+			tc.setupMock(sqlMock)
+			err := tc.call(actor.WithID(context.Background(), actorID), repo)
+			tc.assertion(t, err)
+			assert.NoError(t, sqlMock.ExpectationsWereMet())
+		})
+	}
+}
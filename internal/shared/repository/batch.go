@@ -0,0 +1,159 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	sharedEntity "github.com/ming-0x0/hexago/internal/shared/entity"
+	sharedErrors "github.com/ming-0x0/hexago/internal/shared/errors"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// OnConflict configures the update of Upsert when a row already exists.
+type OnConflict struct {
+	// Columns are the unique key columns that conflict. MySQL detects the
+	// conflicting key itself, but other dialects require it.
+	Columns []string
+	// UpdateColumns are the columns updated from the new row.
+	// default: every column except the primary key and the creation columns
+	UpdateColumns []string
+}
+
+// clause returns the gorm clause of o after checking its columns against the entity.
+func (o OnConflict) clause(s *schema.Schema) (clause.OnConflict, error) {
+	onConflict := clause.OnConflict{UpdateAll: len(o.UpdateColumns) == 0}
+
+	for _, column := range o.Columns {
+		if _, ok := s.FieldsByDBName[column]; !ok {
+			return clause.OnConflict{}, fmt.Errorf("unknown conflict column %q of table %s", column, s.Table)
+		}
+		onConflict.Columns = append(onConflict.Columns, clause.Column{Name: column})
+	}
+
+	for _, column := range o.UpdateColumns {
+		if _, ok := s.FieldsByDBName[column]; !ok {
+			return clause.OnConflict{}, fmt.Errorf("unknown update column %q of table %s", column, s.Table)
+		}
+	}
+	if len(o.UpdateColumns) > 0 {
+		onConflict.DoUpdates = clause.AssignmentColumns(o.UpdateColumns)
+	}
+
+	return onConflict, nil
+}
+
+func (r *Repository[A, D, E]) CreateMany(
+	ctx context.Context,
+	domains []*D,
+) error {
+	if len(domains) == 0 {
+		return nil
+	}
+
+	entities, err := r.adapter.ToEntities(domains)
+	if err != nil {
+		return err
+	}
+
+	if err := r.stamp(ctx, true, entities...); err != nil {
+		return err
+	}
+
+	for _, entity := range entities {
+		if versioned, ok := any(entity).(sharedEntity.VersionedInterface); ok && versioned.CurrentVersion() == 0 {
+			versioned.SetVersion(1)
+		}
+	}
+
+	err = r.DB(ctx).Scopes(r.omitAssociations).CreateInBatches(entities, r.options.batchSize).Error
+	if err != nil {
+		return sharedErrors.NewDomainError(sharedErrors.System, err.Error())
+	}
+
+	if isVersioned[E]() {
+		return r.refreshAll(domains, entities)
+	}
+
+	return nil
+}
+
+// SaveMany inserts the domains, updating every column of the rows that already exist.
+// Unlike Save, it does not check the version of versioned entities: it
+// increments the version of their domains and overwrites the version of their
+// rows with it.
+func (r *Repository[A, D, E]) SaveMany(
+	ctx context.Context,
+	domains []*D,
+) error {
+	return r.upsert(ctx, domains, clause.OnConflict{UpdateAll: true})
+}
+
+// Upsert inserts the domains, updating the rows that already exist as configured by onConflict.
+// The versions of versioned entities are incremented and overwritten as SaveMany does.
+func (r *Repository[A, D, E]) Upsert(
+	ctx context.Context,
+	domains []*D,
+	onConflict OnConflict,
+) error {
+	s, err := r.schema()
+	if err != nil {
+		return err
+	}
+
+	c, err := onConflict.clause(s)
+	if err != nil {
+		return sharedErrors.NewDomainError(sharedErrors.Validation, err.Error())
+	}
+
+	return r.upsert(ctx, domains, c)
+}
+
+func (r *Repository[A, D, E]) upsert(
+	ctx context.Context,
+	domains []*D,
+	onConflict clause.OnConflict,
+) error {
+	if len(domains) == 0 {
+		return nil
+	}
+
+	entities, err := r.adapter.ToEntities(domains)
+	if err != nil {
+		return err
+	}
+
+	if err := r.stamp(ctx, true, entities...); err != nil {
+		return err
+	}
+
+	if isAuditable[E]() || isVersioned[E]() {
+		s, err := r.schema()
+		if err != nil {
+			return err
+		}
+		if isAuditable[E]() {
+			onConflict = auditOnConflict(s, onConflict)
+		}
+		if isVersioned[E]() {
+			bumpVersions(entities)
+			onConflict = versionOnConflict(s, onConflict)
+		}
+	}
+
+	// update_track_time refreshes the update time of the existing rows, as Save does.
+	err = r.DB(ctx).
+		Scopes(r.omitAssociations).
+		Set("gorm:update_track_time", true).
+		Clauses(onConflict).
+		CreateInBatches(entities, r.options.batchSize).Error
+	if err != nil {
+		return sharedErrors.NewDomainError(sharedErrors.System, err.Error())
+	}
+
+	if isVersioned[E]() {
+		return r.refreshAll(domains, entities)
+	}
+
+	return nil
+}
@@ -0,0 +1,218 @@
+package repository
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestRepository_CreateMany(t *testing.T) {
+	t.Parallel()
+
+	domains := []*DummyDomain{
+		{ID: 1, Name: "Test1"},
+		{ID: 2, Name: "Test2"},
+		{ID: 3, Name: "Test3"},
+	}
+
+	tests := []struct {
+		name          string
+		domains       []*DummyDomain
+		adapterConfig DummyAdapter
+		setupMock     func(sqlmock.Sqlmock)
+		assertion     assert.ErrorAssertionFunc
+	}{
+		{
+			name:          "Success_Batches",
+			domains:       domains,
+			adapterConfig: DummyAdapter{},
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.MatchExpectationsInOrder(true)
+				mock.ExpectBegin()
+				mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `dummy_entities` (`name`,`id`) VALUES (?,?),(?,?)")).
+					WithArgs("Test1", 1, "Test2", 2).
+					WillReturnResult(sqlmock.NewResult(2, 2))
+				mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `dummy_entities` (`name`,`id`) VALUES (?,?)")).
+					WithArgs("Test3", 3).
+					WillReturnResult(sqlmock.NewResult(3, 1))
+				mock.ExpectCommit()
+			},
+			assertion: assert.NoError,
+		},
+		{
+			name:          "Success_Empty",
+			domains:       nil,
+			adapterConfig: DummyAdapter{},
+			setupMock:     func(mock sqlmock.Sqlmock) {},
+			assertion:     assert.NoError,
+		},
+		{
+			name:          "Failure_DBError",
+			domains:       domains,
+			adapterConfig: DummyAdapter{},
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec("INSERT INTO `dummy_entities`").WillReturnError(gorm.ErrInvalidData)
+				mock.ExpectRollback()
+			},
+			assertion: assert.Error,
+		},
+		{
+			name:          "Failure_AdapterToEntitiesError",
+			domains:       domains,
+			adapterConfig: DummyAdapter{ShouldFailToEntities: true},
+			setupMock:     func(mock sqlmock.Sqlmock) {},
+			assertion:     assert.Error,
+		},
+	}
+
+	for _, tt := range tests {
+		tc := tt
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			repo, mockedDB, _, sqlMock, _, _ := setupTest(t, tc.adapterConfig, WithBatchSize(2))
+			defer teardownTest(mockedDB)
+
+			tc.setupMock(sqlMock)
+			err := repo.CreateMany(context.Background(), tc.domains)
+			tc.assertion(t, err)
+			assert.NoError(t, sqlMock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestRepository_SaveMany(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		domains       []*DummyDomain
+		adapterConfig DummyAdapter
+		setupMock     func(sqlmock.Sqlmock)
+		assertion     assert.ErrorAssertionFunc
+	}{
+		{
+			name:          "Success",
+			domains:       []*DummyDomain{{ID: 1, Name: "Test1"}, {ID: 2, Name: "Test2"}},
+			adapterConfig: DummyAdapter{},
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(regexp.QuoteMeta(
+					"INSERT INTO `dummy_entities` (`name`,`id`) VALUES (?,?),(?,?) ON DUPLICATE KEY UPDATE `name`=VALUES(`name`)",
+				)).WithArgs("Test1", 1, "Test2", 2).WillReturnResult(sqlmock.NewResult(2, 4))
+				mock.ExpectCommit()
+			},
+			assertion: assert.NoError,
+		},
+		{
+			name:          "Failure_DBError",
+			domains:       []*DummyDomain{{ID: 1, Name: "Test1"}},
+			adapterConfig: DummyAdapter{},
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec("INSERT INTO `dummy_entities`").WillReturnError(gorm.ErrInvalidData)
+				mock.ExpectRollback()
+			},
+			assertion: assert.Error,
+		},
+		{
+			name:          "Failure_AdapterToEntitiesError",
+			domains:       []*DummyDomain{{ID: 1, Name: "Test1"}},
+			adapterConfig: DummyAdapter{ShouldFailToEntities: true},
+			setupMock:     func(mock sqlmock.Sqlmock) {},
+			assertion:     assert.Error,
+		},
+	}
+
+	for _, tt := range tests {
+		tc := tt
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			repo, mockedDB, _, sqlMock, _, _ := setupTest(t, tc.adapterConfig)
+			defer teardownTest(mockedDB)
+
+			tc.setupMock(sqlMock)
+			err := repo.SaveMany(context.Background(), tc.domains)
+			tc.assertion(t, err)
+			assert.NoError(t, sqlMock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestRepository_Upsert(t *testing.T) {
+	t.Parallel()
+
+	domains := []*DummyDomain{{ID: 1, Name: "Test1"}, {ID: 2, Name: "Test2"}}
+
+	tests := []struct {
+		name       string
+		onConflict OnConflict
+		setupMock  func(sqlmock.Sqlmock)
+		assertion  assert.ErrorAssertionFunc
+	}{
+		{
+			name:       "Success_UpdateColumns",
+			onConflict: OnConflict{Columns: []string{"id"}, UpdateColumns: []string{"name"}},
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(regexp.QuoteMeta(
+					"INSERT INTO `dummy_entities` (`name`,`id`) VALUES (?,?),(?,?) ON DUPLICATE KEY UPDATE `name`=VALUES(`name`)",
+				)).WithArgs("Test1", 1, "Test2", 2).WillReturnResult(sqlmock.NewResult(2, 2))
+				mock.ExpectCommit()
+			},
+			assertion: assert.NoError,
+		},
+		{
+			name:       "Success_UpdateAll",
+			onConflict: OnConflict{},
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(regexp.QuoteMeta(
+					"INSERT INTO `dummy_entities` (`name`,`id`) VALUES (?,?),(?,?) ON DUPLICATE KEY UPDATE `name`=VALUES(`name`)",
+				)).WithArgs("Test1", 1, "Test2", 2).WillReturnResult(sqlmock.NewResult(2, 2))
+				mock.ExpectCommit()
+			},
+			assertion: assert.NoError,
+		},
+		{
+			name:       "Failure_UnknownConflictColumn",
+			onConflict: OnConflict{Columns: []string{"email"}},
+			setupMock:  func(mock sqlmock.Sqlmock) {},
+			assertion:  assert.Error,
+		},
+		{
+			name:       "Failure_UnknownUpdateColumn",
+			onConflict: OnConflict{UpdateColumns: []string{"Name"}},
+			setupMock:  func(mock sqlmock.Sqlmock) {},
+			assertion:  assert.Error,
+		},
+		{
+			name:       "Failure_DBError",
+			onConflict: OnConflict{UpdateColumns: []string{"name"}},
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec("INSERT INTO `dummy_entities`").WillReturnError(gorm.ErrInvalidData)
+				mock.ExpectRollback()
+			},
+			assertion: assert.Error,
+		},
+	}
+
+	for _, tt := range tests {
+		tc := tt
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			repo, mockedDB, _, sqlMock, _, _ := setupTest(t, DummyAdapter{})
+			defer teardownTest(mockedDB)
+
+			tc.setupMock(sqlMock)
+			err := repo.Upsert(context.Background(), domains, tc.onConflict)
+			tc.assertion(t, err)
+			assert.NoError(t, sqlMock.ExpectationsWereMet())
+		})
+	}
+}
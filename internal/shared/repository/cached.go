@@ -0,0 +1,287 @@
+package repository
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/ming-0x0/hexago/internal/shared/cache"
+	"github.com/ming-0x0/hexago/internal/shared/specification"
+	"github.com/ming-0x0/hexago/internal/shared/transaction"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const DefaultCacheTTL = time.Minute
+
+type cacheOptions struct {
+	ttl      time.Duration
+	queryTTL time.Duration
+}
+
+// CacheOption configures a CachedRepository.
+type CacheOption func(*cacheOptions)
+
+// WithCacheTTL sets how long a domain taken by primary key stays cached.
+// default: DefaultCacheTTL
+func WithCacheTTL(ttl time.Duration) CacheOption {
+	return func(o *cacheOptions) {
+		if ttl > 0 {
+			o.ttl = ttl
+		}
+	}
+}
+
+// WithQueryCacheTTL caches the results of the other queries of
+// FindByConditions and TakeByConditions for ttl. default: not cached
+func WithQueryCacheTTL(ttl time.Duration) CacheOption {
+	return func(o *cacheOptions) {
+		o.queryTTL = ttl
+	}
+}
+
+// CachedRepository decorates a repository with a cache of the reads of
+// TakeByConditions and FindByConditions. Every write invalidates the cached
+// values of the entity, once its transaction is committed when it runs in one
+// started by transaction.Do. The cache is bypassed inside a transaction, with
+// a context from WithPrimary, and for queries with scopes, which have no key.
+// Domains are cached and returned as copies made by their Clone method if they
+// have one, and by assignment otherwise, which shares their slices, maps and
+// pointers between callers.
+type CachedRepository[A AdapterInterface[D, E], D, E any] struct {
+	RepositoryInterface[A, D, E]
+	cache   cache.CacheInterface
+	options cacheOptions
+	// db renders the queries whose results are cached into keys.
+	db *gorm.DB
+	// prefix starts the keys of the values of the entity.
+	prefix string
+	// pk is the primary key column, empty if the entity has none.
+	pk string
+}
+
+func NewCachedRepository[A AdapterInterface[D, E], D, E any](
+	repo RepositoryInterface[A, D, E],
+	db *gorm.DB,
+	c cache.CacheInterface,
+	opts ...CacheOption,
+) *CachedRepository[A, D, E] {
+	o := cacheOptions{
+		ttl: DefaultCacheTTL,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	r := &CachedRepository[A, D, E]{
+		RepositoryInterface: repo,
+		cache:               c,
+		options:             o,
+		db:                  db.Session(&gorm.Session{DryRun: true, NewDB: true}),
+		prefix:              reflect.TypeFor[E]().String() + ":",
+	}
+	stmt := &gorm.Statement{DB: db}
+	if err := stmt.Parse(new(E)); err == nil {
+		r.prefix = stmt.Schema.Table + ":"
+		if stmt.Schema.PrioritizedPrimaryField != nil {
+			r.pk = stmt.Schema.PrioritizedPrimaryField.DBName
+		}
+	}
+
+	return r
+}
+
+// bypass reports whether the reads of ctx must not use the cache.
+func (r *CachedRepository[A, D, E]) bypass(ctx context.Context, scopes []func(*gorm.DB) *gorm.DB) bool {
+	if _, ok := transaction.TransactionFromContext(ctx); ok {
+		return true
+	}
+	return primaryFromContext(ctx) || len(scopes) > 0
+}
+
+// key returns the cache key of a query of spec and its ttl. A take by primary
+// key is cached with the ttl of the repository, other queries with the ttl of
+// queries under the SQL they render to, and no key is returned when that ttl
+// is 0.
+func (r *CachedRepository[A, D, E]) key(query string, spec specification.Specification) (string, time.Duration) {
+	var expr clause.Expression
+	var orders []specification.Order
+	if spec != nil {
+		expr, orders = spec.Expression(), specification.Orders(spec)
+	}
+
+	if eq, ok := expr.(clause.Eq); ok && query == "take" && len(orders) == 0 {
+		if column, ok := eq.Column.(clause.Column); ok && r.pk != "" && column.Name == r.pk {
+			return fmt.Sprintf("%stake:%#v", r.prefix, keyValue(eq.Value)), r.options.ttl
+		}
+	}
+
+	if r.options.queryTTL <= 0 {
+		return "", 0
+	}
+
+	db := r.db.Model(new(E))
+	if expr != nil {
+		db = db.Where(expr)
+	}
+	if orderBy := specification.Clause(orders); orderBy != nil {
+		db = db.Clauses(orderBy)
+	}
+	stmt := db.Find(&[]E{}).Statement
+	if stmt.Error != nil {
+		return "", 0
+	}
+
+	values := make([]any, len(stmt.Vars))
+	for i, v := range stmt.Vars {
+		values[i] = keyValue(v)
+	}
+	return fmt.Sprintf("%s%s:%s:%#v", r.prefix, query, stmt.SQL.String(), values), r.options.queryTTL
+}
+
+// keyValue returns the value a query argument v is written with, so that
+// equal arguments behind different pointers give the same key.
+func keyValue(v any) any {
+	for {
+		rv := reflect.ValueOf(v)
+		if rv.Kind() == reflect.Pointer && rv.IsNil() {
+			return nil
+		}
+		if valuer, ok := v.(driver.Valuer); ok {
+			if value, err := valuer.Value(); err == nil {
+				return value
+			}
+			return v
+		}
+		if rv.Kind() != reflect.Pointer {
+			return v
+		}
+		v = rv.Elem().Interface()
+	}
+}
+
+// clone copies domain with its Clone method if it has one.
+func clone[D any](domain D) D {
+	if c, ok := any(domain).(interface{ Clone() D }); ok {
+		return c.Clone()
+	}
+	return domain
+}
+
+func (r *CachedRepository[A, D, E]) TakeByConditions(
+	ctx context.Context,
+	spec specification.Specification,
+	scopes ...func(*gorm.DB) *gorm.DB,
+) (*D, error) {
+	if r.bypass(ctx, scopes) {
+		return r.RepositoryInterface.TakeByConditions(ctx, spec, scopes...)
+	}
+
+	key, ttl := r.key("take", spec)
+	if key == "" {
+		return r.RepositoryInterface.TakeByConditions(ctx, spec)
+	}
+	if v, ok := r.cache.Get(ctx, key); ok {
+		if domain, ok := v.(D); ok {
+			domain = clone(domain)
+			return &domain, nil
+		}
+	}
+
+	domain, err := r.RepositoryInterface.TakeByConditions(ctx, spec)
+	if err != nil {
+		return nil, err
+	}
+
+	// values are cached and returned as copies, so that callers cannot change them.
+	r.cache.Set(ctx, key, clone(*domain), ttl)
+	return domain, nil
+}
+
+func (r *CachedRepository[A, D, E]) FindByConditions(
+	ctx context.Context,
+	spec specification.Specification,
+	scopes ...func(*gorm.DB) *gorm.DB,
+) ([]*D, error) {
+	if r.bypass(ctx, scopes) {
+		return r.RepositoryInterface.FindByConditions(ctx, spec, scopes...)
+	}
+
+	key, ttl := r.key("find", spec)
+	if key == "" {
+		return r.RepositoryInterface.FindByConditions(ctx, spec)
+	}
+	if v, ok := r.cache.Get(ctx, key); ok {
+		if values, ok := v.([]D); ok {
+			domains := make([]*D, len(values))
+			for i := range values {
+				domain := clone(values[i])
+				domains[i] = &domain
+			}
+			return domains, nil
+		}
+	}
+
+	domains, err := r.RepositoryInterface.FindByConditions(ctx, spec)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make([]D, len(domains))
+	for i, domain := range domains {
+		values[i] = clone(*domain)
+	}
+	r.cache.Set(ctx, key, values, ttl)
+	return domains, nil
+}
+
+// invalidate removes the cached values of the entity after a write, even a
+// failed one, which may have changed rows before failing. Within a transaction
+// it waits for the commit, so that no read caches the former rows meanwhile.
+func (r *CachedRepository[A, D, E]) invalidate(ctx context.Context) {
+	transaction.AfterCommit(ctx, func() {
+		r.cache.DeletePrefix(ctx, r.prefix)
+	})
+}
+
+func (r *CachedRepository[A, D, E]) Create(ctx context.Context, domain *D) error {
+	defer r.invalidate(ctx)
+	return r.RepositoryInterface.Create(ctx, domain)
+}
+
+func (r *CachedRepository[A, D, E]) Save(ctx context.Context, domain *D) error {
+	defer r.invalidate(ctx)
+	return r.RepositoryInterface.Save(ctx, domain)
+}
+
+func (r *CachedRepository[A, D, E]) DeleteByConditions(ctx context.Context, spec specification.Specification) error {
+	defer r.invalidate(ctx)
+	return r.RepositoryInterface.DeleteByConditions(ctx, spec)
+}
+
+func (r *CachedRepository[A, D, E]) Restore(ctx context.Context, spec specification.Specification) error {
+	defer r.invalidate(ctx)
+	return r.RepositoryInterface.Restore(ctx, spec)
+}
+
+func (r *CachedRepository[A, D, E]) ForceDelete(ctx context.Context, spec specification.Specification) error {
+	defer r.invalidate(ctx)
+	return r.RepositoryInterface.ForceDelete(ctx, spec)
+}
+
+func (r *CachedRepository[A, D, E]) CreateMany(ctx context.Context, domains []*D) error {
+	defer r.invalidate(ctx)
+	return r.RepositoryInterface.CreateMany(ctx, domains)
+}
+
+func (r *CachedRepository[A, D, E]) SaveMany(ctx context.Context, domains []*D) error {
+	defer r.invalidate(ctx)
+	return r.RepositoryInterface.SaveMany(ctx, domains)
+}
+
+func (r *CachedRepository[A, D, E]) Upsert(ctx context.Context, domains []*D, onConflict OnConflict) error {
+	defer r.invalidate(ctx)
+	return r.RepositoryInterface.Upsert(ctx, domains, onConflict)
+}
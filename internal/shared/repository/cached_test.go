@@ -0,0 +1,262 @@
+package repository
+
+import (
+	"context"
+	"regexp"
+	"slices"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/ming-0x0/hexago/internal/shared/cache"
+	"github.com/ming-0x0/hexago/internal/shared/specification"
+	"github.com/ming-0x0/hexago/internal/shared/transaction"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestCachedRepository(t *testing.T) {
+	t.Parallel()
+
+	const takeSQL = "SELECT * FROM `dummy_entities` WHERE `dummy_entities`.`id` = ? LIMIT ?"
+	const findSQL = "SELECT * FROM `dummy_entities` WHERE `dummy_entities`.`name` = ?"
+
+	expectTake := func(mock sqlmock.Sqlmock, name string) {
+		mock.ExpectQuery(regexp.QuoteMeta(takeSQL)).WithArgs(1, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(1, name))
+	}
+	expectFind := func(mock sqlmock.Sqlmock) {
+		mock.ExpectQuery(regexp.QuoteMeta(findSQL)).WithArgs("Test").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "Test"))
+	}
+
+	type repo = *CachedRepository[*DummyAdapter, DummyDomain, DummyEntity]
+
+	tests := []struct {
+		name      string
+		opts      []CacheOption
+		setupMock func(sqlmock.Sqlmock)
+		run       func(t *testing.T, ctx context.Context, r repo)
+	}{
+		{
+			name: "Take_CachedByPrimaryKey",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				expectTake(mock, "Test")
+			},
+			run: func(t *testing.T, ctx context.Context, r repo) {
+				for range 2 {
+					got, err := r.TakeByConditions(ctx, specification.Eq("id", 1))
+					assert.NoError(t, err)
+					assert.Equal(t, &DummyDomain{ID: 1, Name: "Test"}, got)
+				}
+			},
+		},
+		{
+			name: "Take_ReturnsCopies",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				expectTake(mock, "Test")
+			},
+			run: func(t *testing.T, ctx context.Context, r repo) {
+				got, err := r.TakeByConditions(ctx, specification.Eq("id", 1))
+				assert.NoError(t, err)
+				got.Name = "Changed"
+
+				got, err = r.TakeByConditions(ctx, specification.Eq("id", 1))
+				assert.NoError(t, err)
+				assert.Equal(t, "Test", got.Name)
+			},
+		},
+		{
+			name: "Take_InvalidatedBySave",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				expectTake(mock, "Old")
+				mock.ExpectBegin()
+				mock.ExpectExec("UPDATE `dummy_entities`").WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+				expectTake(mock, "New")
+			},
+			run: func(t *testing.T, ctx context.Context, r repo) {
+				_, err := r.TakeByConditions(ctx, specification.Eq("id", 1))
+				assert.NoError(t, err)
+				assert.NoError(t, r.Save(ctx, &DummyDomain{ID: 1, Name: "New"}))
+
+				got, err := r.TakeByConditions(ctx, specification.Eq("id", 1))
+				assert.NoError(t, err)
+				assert.Equal(t, "New", got.Name)
+			},
+		},
+		{
+			name: "Take_InvalidatedAfterCommit",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				expectTake(mock, "Old")
+				mock.ExpectBegin()
+				mock.ExpectExec("UPDATE `dummy_entities`").WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+				expectTake(mock, "New")
+			},
+			run: func(t *testing.T, ctx context.Context, r repo) {
+				_, err := r.TakeByConditions(ctx, specification.Eq("id", 1))
+				assert.NoError(t, err)
+
+				base := r.RepositoryInterface.(*Repository[*DummyAdapter, DummyDomain, DummyEntity])
+				err = transaction.NewTransaction(base.db).Do(ctx, func(txCtx context.Context) error {
+					if err := r.Save(txCtx, &DummyDomain{ID: 1, Name: "New"}); err != nil {
+						return err
+					}
+
+					// reads outside the transaction keep the committed row until the commit.
+					got, err := r.TakeByConditions(ctx, specification.Eq("id", 1))
+					assert.NoError(t, err)
+					assert.Equal(t, "Old", got.Name)
+					return nil
+				})
+				assert.NoError(t, err)
+
+				got, err := r.TakeByConditions(ctx, specification.Eq("id", 1))
+				assert.NoError(t, err)
+				assert.Equal(t, "New", got.Name)
+			},
+		},
+		{
+			name: "Take_NotFoundNotCached",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta(takeSQL)).WithArgs(1, 1).WillReturnError(gorm.ErrRecordNotFound)
+				expectTake(mock, "Test")
+			},
+			run: func(t *testing.T, ctx context.Context, r repo) {
+				_, err := r.TakeByConditions(ctx, specification.Eq("id", 1))
+				assert.Error(t, err)
+
+				_, err = r.TakeByConditions(ctx, specification.Eq("id", 1))
+				assert.NoError(t, err)
+			},
+		},
+		{
+			name: "Take_BypassedInTransaction",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				expectTake(mock, "Test")
+				mock.ExpectBegin()
+				expectTake(mock, "Test")
+				mock.ExpectCommit()
+			},
+			run: func(t *testing.T, ctx context.Context, r repo) {
+				_, err := r.TakeByConditions(ctx, specification.Eq("id", 1))
+				assert.NoError(t, err)
+
+				base := r.RepositoryInterface.(*Repository[*DummyAdapter, DummyDomain, DummyEntity])
+				err = transaction.NewTransaction(base.db).Do(ctx, func(ctx context.Context) error {
+					_, err := r.TakeByConditions(ctx, specification.Eq("id", 1))
+					return err
+				})
+				assert.NoError(t, err)
+			},
+		},
+		{
+			name: "Find_NotCachedByDefault",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				expectFind(mock)
+				expectFind(mock)
+			},
+			run: func(t *testing.T, ctx context.Context, r repo) {
+				for range 2 {
+					_, err := r.FindByConditions(ctx, specification.Eq("name", "Test"))
+					assert.NoError(t, err)
+				}
+			},
+		},
+		{
+			name: "Find_CachedWithQueryTTL",
+			opts: []CacheOption{WithQueryCacheTTL(DefaultCacheTTL)},
+			setupMock: func(mock sqlmock.Sqlmock) {
+				expectFind(mock)
+				mock.ExpectBegin()
+				mock.ExpectExec("DELETE FROM `dummy_entities`").WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+				expectFind(mock)
+			},
+			run: func(t *testing.T, ctx context.Context, r repo) {
+				for range 2 {
+					got, err := r.FindByConditions(ctx, specification.Eq("name", "Test"))
+					assert.NoError(t, err)
+					assert.Equal(t, []*DummyDomain{{ID: 1, Name: "Test"}}, got)
+				}
+
+				assert.NoError(t, r.DeleteByConditions(ctx, specification.Eq("id", 2)))
+				_, err := r.FindByConditions(ctx, specification.Eq("name", "Test"))
+				assert.NoError(t, err)
+			},
+		},
+		{
+			name: "Find_CachedWithPointerArguments",
+			opts: []CacheOption{WithQueryCacheTTL(DefaultCacheTTL)},
+			setupMock: func(mock sqlmock.Sqlmock) {
+				expectFind(mock)
+			},
+			run: func(t *testing.T, ctx context.Context, r repo) {
+				for range 2 {
+					name := "Test"
+					got, err := r.FindByConditions(ctx, specification.Eq("name", &name))
+					assert.NoError(t, err)
+					assert.Equal(t, []*DummyDomain{{ID: 1, Name: "Test"}}, got)
+				}
+			},
+		},
+		{
+			name: "Find_BypassedWithScopes",
+			opts: []CacheOption{WithQueryCacheTTL(DefaultCacheTTL)},
+			setupMock: func(mock sqlmock.Sqlmock) {
+				expectFind(mock)
+				expectFind(mock)
+			},
+			run: func(t *testing.T, ctx context.Context, r repo) {
+				noop := func(db *gorm.DB) *gorm.DB { return db }
+				for range 2 {
+					_, err := r.FindByConditions(ctx, specification.Eq("name", "Test"), noop)
+					assert.NoError(t, err)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tc := tt
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			base, mockedDB, _, sqlMock, _, _ := setupTest(t, DummyAdapter{})
+			defer teardownTest(mockedDB)
+			sqlMock.MatchExpectationsInOrder(true)
+
+			tc.setupMock(sqlMock)
+			r := NewCachedRepository[*DummyAdapter, DummyDomain, DummyEntity](base, mockedDB.GormDB, cache.NewLRU(10), tc.opts...)
+			tc.run(t, context.Background(), r)
+			assert.NoError(t, sqlMock.ExpectationsWereMet())
+		})
+	}
+}
+
+func (d DummyParentDomain) Clone() DummyParentDomain {
+	d.Children = slices.Clone(d.Children)
+	return d
+}
+
+func TestCachedRepository_ReturnsClones(t *testing.T) {
+	t.Parallel()
+
+	base, mockedDB, sqlMock := setupAssociationTest(t, "Children")
+	defer teardownTest(mockedDB)
+
+	sqlMock.ExpectQuery(regexp.QuoteMeta(
+		"SELECT * FROM `dummy_parent_entities` WHERE `dummy_parent_entities`.`id` = ? LIMIT ?",
+	)).WithArgs(1, 1).WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "Parent"))
+	sqlMock.ExpectQuery(regexp.QuoteMeta(
+		"SELECT * FROM `dummy_child_entities` WHERE `dummy_child_entities`.`parent_id` = ?",
+	)).WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"id", "parent_id", "name"}).AddRow(10, 1, "A"))
+
+	r := NewCachedRepository[*DummyParentAdapter, DummyParentDomain, DummyParentEntity](base, mockedDB.GormDB, cache.NewLRU(10))
+	for range 3 {
+		got, err := r.TakeByConditions(context.Background(), specification.Eq("id", 1))
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"A"}, got.Children)
+		got.Children[0] = "Changed"
+	}
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
@@ -0,0 +1,198 @@
+package repository
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	sharedErrors "github.com/ming-0x0/hexago/internal/shared/errors"
+	"github.com/ming-0x0/hexago/internal/shared/specification"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// CursorPage requests a page of rows ordered by primary key.
+type CursorPage struct {
+	// Cursor is NextCursor or PrevCursor of a previous CursorResult, empty for the first page.
+	Cursor string `json:"cursor"`
+	// Limit is the number of rows of the page, at most MaxPerPage. default: DefaultPerPage
+	Limit int `json:"limit"`
+	// Desc orders the rows by descending primary key. It must be the order of
+	// the page Cursor was taken from.
+	Desc bool `json:"desc"`
+}
+
+func (p CursorPage) Validate() error {
+	err := validation.ValidateStruct(&p,
+		validation.Field(&p.Limit, validation.Min(0), validation.Max(MaxPerPage)),
+	)
+	if err != nil {
+		return sharedErrors.NewDomainError(sharedErrors.Validation, err.Error())
+	}
+
+	return nil
+}
+
+// CursorResult is a page of domains with the cursors of its neighbouring pages.
+type CursorResult[D any] struct {
+	Items []*D `json:"items"`
+	// NextCursor is empty when there is no next page.
+	NextCursor string `json:"next_cursor"`
+	// PrevCursor is empty when there is no previous page.
+	PrevCursor string `json:"prev_cursor"`
+}
+
+// cursor is the decoded form of the opaque cursor string.
+type cursor struct {
+	// Key is the JSON encoded primary key the page starts after.
+	Key json.RawMessage `json:"k"`
+	// Backward fetches the rows before Key instead of after it.
+	Backward bool `json:"b,omitempty"`
+	// Desc is the order of the page the cursor was taken from.
+	Desc bool `json:"d,omitempty"`
+}
+
+func encodeCursor(key any, backward, desc bool) (string, error) {
+	raw, err := json.Marshal(key)
+	if err != nil {
+		return "", err
+	}
+
+	data, err := json.Marshal(cursor{Key: raw, Backward: backward, Desc: desc})
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// decodeCursor decodes s, converting its key to the type of field. The cursor
+// must have been taken from a page ordered as desc.
+func decodeCursor(s string, field *schema.Field, desc bool) (any, bool, error) {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, false, fmt.Errorf("invalid cursor: %w", err)
+	}
+
+	var c cursor
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, false, fmt.Errorf("invalid cursor: %w", err)
+	}
+	if c.Desc != desc {
+		return nil, false, fmt.Errorf("cursor was taken from a page of the opposite order")
+	}
+
+	key := reflect.New(field.FieldType)
+	if err := json.Unmarshal(c.Key, key.Interface()); err != nil {
+		return nil, false, fmt.Errorf("invalid cursor key: %w", err)
+	}
+	return key.Elem().Interface(), c.Backward, nil
+}
+
+// FindByConditionsWithCursor returns a page of the domains matching spec using
+// keyset pagination on the primary key, which neither counts the rows nor skips
+// them with OFFSET. The orders of spec are ignored.
+func (r *Repository[A, D, E]) FindByConditionsWithCursor(
+	ctx context.Context,
+	page CursorPage,
+	spec specification.Specification,
+	scopes ...func(*gorm.DB) *gorm.DB,
+) (CursorResult[D], error) {
+	if err := page.Validate(); err != nil {
+		return CursorResult[D]{}, err
+	}
+
+	where, _, err := r.conditions(spec)
+	if err != nil {
+		return CursorResult[D]{}, err
+	}
+
+	s, err := r.schema()
+	if err != nil {
+		return CursorResult[D]{}, err
+	}
+	pk := s.PrioritizedPrimaryField
+	if pk == nil {
+		return CursorResult[D]{}, sharedErrors.NewDomainError(sharedErrors.System, fmt.Sprintf("table %s has no primary key for cursor pagination", s.Table))
+	}
+
+	preload, err := r.preload()
+	if err != nil {
+		return CursorResult[D]{}, err
+	}
+
+	limit := page.Limit
+	if limit <= 0 {
+		limit = DefaultPerPage
+	}
+
+	var key any
+	var backward bool
+	if page.Cursor != "" {
+		key, backward, err = decodeCursor(page.Cursor, pk, page.Desc)
+		if err != nil {
+			return CursorResult[D]{}, sharedErrors.NewDomainError(sharedErrors.BadRequest, err.Error())
+		}
+	}
+
+	// rows before the cursor are fetched in reverse order, then reversed back.
+	desc := page.Desc != backward
+	column := clause.Column{Table: clause.CurrentTable, Name: pk.DBName}
+	db := r.Reader(ctx).Scopes(scopes...).Scopes(where)
+	if page.Cursor != "" {
+		if desc {
+			db = db.Where(clause.Lt{Column: column, Value: key})
+		} else {
+			db = db.Where(clause.Gt{Column: column, Value: key})
+		}
+	}
+
+	// one more row than the limit tells whether there is a further page.
+	var entities []*E
+	err = db.Scopes(preload).Order(clause.OrderByColumn{Column: column, Desc: desc}).Limit(limit + 1).Find(&entities).Error
+	if err != nil {
+		return CursorResult[D]{}, sharedErrors.NewDomainError(sharedErrors.System, err.Error())
+	}
+
+	more := len(entities) > limit
+	if more {
+		entities = entities[:limit]
+	}
+	if backward {
+		slices.Reverse(entities)
+	}
+
+	domains, err := r.adapter.ToDomains(entities)
+	if err != nil {
+		return CursorResult[D]{}, err
+	}
+
+	result := CursorResult[D]{Items: domains}
+	if len(entities) == 0 {
+		return result, nil
+	}
+
+	first, _ := pk.ValueOf(ctx, reflect.ValueOf(entities[0]))
+	last, _ := pk.ValueOf(ctx, reflect.ValueOf(entities[len(entities)-1]))
+	hasNext, hasPrev := more, page.Cursor != ""
+	if backward {
+		hasNext, hasPrev = true, more
+	}
+
+	if hasNext {
+		if result.NextCursor, err = encodeCursor(last, false, page.Desc); err != nil {
+			return CursorResult[D]{}, sharedErrors.NewDomainError(sharedErrors.System, err.Error())
+		}
+	}
+	if hasPrev {
+		if result.PrevCursor, err = encodeCursor(first, true, page.Desc); err != nil {
+			return CursorResult[D]{}, sharedErrors.NewDomainError(sharedErrors.System, err.Error())
+		}
+	}
+
+	return result, nil
+}
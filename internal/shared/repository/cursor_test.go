@@ -0,0 +1,210 @@
+package repository
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	sharedErrors "github.com/ming-0x0/hexago/internal/shared/errors"
+	"github.com/ming-0x0/hexago/internal/shared/specification"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func mustEncodeCursor(t *testing.T, key any, backward, desc bool) string {
+	t.Helper()
+
+	c, err := encodeCursor(key, backward, desc)
+	if err != nil {
+		t.Fatalf("error when encoding cursor: %v", err)
+	}
+	return c
+}
+
+func TestRepository_FindByConditionsWithCursor(t *testing.T) {
+	t.Parallel()
+
+	type args struct {
+		page CursorPage
+		spec specification.Specification
+	}
+
+	tests := []struct {
+		name          string
+		args          args
+		adapterConfig DummyAdapter
+		setupMock     func(sqlmock.Sqlmock)
+		assertion     assert.ErrorAssertionFunc
+		errCode       sharedErrors.ErrorCode
+		expected      []*DummyDomain
+		nextKey       any
+		prevKey       any
+	}{
+		{
+			name:          "Success_FirstPage",
+			args:          args{page: CursorPage{Limit: 2}, spec: specification.Eq("name", "Test")},
+			adapterConfig: DummyAdapter{},
+			setupMock: func(mock sqlmock.Sqlmock) {
+				rows := sqlmock.NewRows([]string{"id", "name"}).
+					AddRow(1, "Test").
+					AddRow(2, "Test").
+					AddRow(3, "Test")
+				mock.ExpectQuery(regexp.QuoteMeta(
+					"SELECT * FROM `dummy_entities` WHERE `dummy_entities`.`name` = ? ORDER BY `dummy_entities`.`id` LIMIT ?",
+				)).WithArgs("Test", 3).WillReturnRows(rows)
+			},
+			assertion: assert.NoError,
+			expected:  []*DummyDomain{{ID: 1, Name: "Test"}, {ID: 2, Name: "Test"}},
+			nextKey:   2,
+		},
+		{
+			name:          "Success_NextPage",
+			args:          args{page: CursorPage{Cursor: mustEncodeCursor(t, 2, false, false), Limit: 2}},
+			adapterConfig: DummyAdapter{},
+			setupMock: func(mock sqlmock.Sqlmock) {
+				rows := sqlmock.NewRows([]string{"id", "name"}).
+					AddRow(3, "Test").
+					AddRow(4, "Test")
+				mock.ExpectQuery(regexp.QuoteMeta(
+					"SELECT * FROM `dummy_entities` WHERE `dummy_entities`.`id` > ? ORDER BY `dummy_entities`.`id` LIMIT ?",
+				)).WithArgs(2, 3).WillReturnRows(rows)
+			},
+			assertion: assert.NoError,
+			expected:  []*DummyDomain{{ID: 3, Name: "Test"}, {ID: 4, Name: "Test"}},
+			prevKey:   3,
+		},
+		{
+			name:          "Success_PrevPage",
+			args:          args{page: CursorPage{Cursor: mustEncodeCursor(t, 5, true, false), Limit: 2}},
+			adapterConfig: DummyAdapter{},
+			setupMock: func(mock sqlmock.Sqlmock) {
+				rows := sqlmock.NewRows([]string{"id", "name"}).
+					AddRow(4, "Test").
+					AddRow(3, "Test").
+					AddRow(2, "Test")
+				mock.ExpectQuery(regexp.QuoteMeta(
+					"SELECT * FROM `dummy_entities` WHERE `dummy_entities`.`id` < ? ORDER BY `dummy_entities`.`id` DESC LIMIT ?",
+				)).WithArgs(5, 3).WillReturnRows(rows)
+			},
+			assertion: assert.NoError,
+			expected:  []*DummyDomain{{ID: 3, Name: "Test"}, {ID: 4, Name: "Test"}},
+			nextKey:   4,
+			prevKey:   3,
+		},
+		{
+			name:          "Success_DescNextPage",
+			args:          args{page: CursorPage{Cursor: mustEncodeCursor(t, 5, false, true), Limit: 2, Desc: true}},
+			adapterConfig: DummyAdapter{},
+			setupMock: func(mock sqlmock.Sqlmock) {
+				rows := sqlmock.NewRows([]string{"id", "name"}).
+					AddRow(4, "Test").
+					AddRow(3, "Test").
+					AddRow(2, "Test")
+				mock.ExpectQuery(regexp.QuoteMeta(
+					"SELECT * FROM `dummy_entities` WHERE `dummy_entities`.`id` < ? ORDER BY `dummy_entities`.`id` DESC LIMIT ?",
+				)).WithArgs(5, 3).WillReturnRows(rows)
+			},
+			assertion: assert.NoError,
+			expected:  []*DummyDomain{{ID: 4, Name: "Test"}, {ID: 3, Name: "Test"}},
+			nextKey:   3,
+			prevKey:   4,
+		},
+		{
+			name:          "Success_Empty",
+			args:          args{page: CursorPage{}},
+			adapterConfig: DummyAdapter{},
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta(
+					"SELECT * FROM `dummy_entities` ORDER BY `dummy_entities`.`id` LIMIT ?",
+				)).WithArgs(DefaultPerPage + 1).WillReturnRows(sqlmock.NewRows([]string{"id", "name"}))
+			},
+			assertion: assert.NoError,
+			expected:  []*DummyDomain{},
+		},
+		{
+			name:          "Failure_LimitTooLarge",
+			args:          args{page: CursorPage{Limit: MaxPerPage + 1}},
+			adapterConfig: DummyAdapter{},
+			setupMock:     func(mock sqlmock.Sqlmock) {},
+			assertion:     assert.Error,
+			errCode:       sharedErrors.Validation,
+		},
+		{
+			name:          "Failure_InvalidCursor",
+			args:          args{page: CursorPage{Cursor: "not a cursor"}},
+			adapterConfig: DummyAdapter{},
+			setupMock:     func(mock sqlmock.Sqlmock) {},
+			assertion:     assert.Error,
+			errCode:       sharedErrors.BadRequest,
+		},
+		{
+			name:          "Failure_CursorOfOppositeOrder",
+			args:          args{page: CursorPage{Cursor: mustEncodeCursor(t, 5, false, true), Limit: 2}},
+			adapterConfig: DummyAdapter{},
+			setupMock:     func(mock sqlmock.Sqlmock) {},
+			assertion:     assert.Error,
+			errCode:       sharedErrors.BadRequest,
+		},
+		{
+			name:          "Failure_InvalidCursorKey",
+			args:          args{page: CursorPage{Cursor: mustEncodeCursor(t, "01ARZ3NDEKTSV4RRFFQ69G5FAV", false, false)}},
+			adapterConfig: DummyAdapter{},
+			setupMock:     func(mock sqlmock.Sqlmock) {},
+			assertion:     assert.Error,
+			errCode:       sharedErrors.BadRequest,
+		},
+		{
+			name:          "Failure_DBError",
+			args:          args{page: CursorPage{Limit: 2}},
+			adapterConfig: DummyAdapter{},
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SELECT (.+) FROM `dummy_entities`").WillReturnError(gorm.ErrInvalidField)
+			},
+			assertion: assert.Error,
+			errCode:   sharedErrors.System,
+		},
+		{
+			name:          "Failure_AdapterToDomainsError",
+			args:          args{page: CursorPage{Limit: 2}},
+			adapterConfig: DummyAdapter{ShouldFailToDomains: true},
+			setupMock: func(mock sqlmock.Sqlmock) {
+				rows := sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "Test")
+				mock.ExpectQuery("SELECT (.+) FROM `dummy_entities`").WillReturnRows(rows)
+			},
+			assertion: assert.Error,
+			errCode:   sharedErrors.Validation,
+		},
+	}
+
+	for _, tt := range tests {
+		tc := tt
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			repo, mockedDB, _, sqlMock, _, _ := setupTest(t, tc.adapterConfig)
+			defer teardownTest(mockedDB)
+
+			tc.setupMock(sqlMock)
+			result, err := repo.FindByConditionsWithCursor(context.Background(), tc.args.page, tc.args.spec)
+			tc.assertion(t, err)
+			if err != nil {
+				var domainErr *sharedErrors.DomainError
+				assert.ErrorAs(t, err, &domainErr)
+				assert.Equal(t, tc.errCode, domainErr.ErrorCode())
+				return
+			}
+
+			assert.Equal(t, tc.expected, result.Items)
+			if tc.nextKey != nil {
+				assert.Equal(t, mustEncodeCursor(t, tc.nextKey, false, tc.args.page.Desc), result.NextCursor)
+			} else {
+				assert.Empty(t, result.NextCursor)
+			}
+			if tc.prevKey != nil {
+				assert.Equal(t, mustEncodeCursor(t, tc.prevKey, true, tc.args.page.Desc), result.PrevCursor)
+			} else {
+				assert.Empty(t, result.PrevCursor)
+			}
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"iter"
+
+	sharedErrors "github.com/ming-0x0/hexago/internal/shared/errors"
+	"github.com/ming-0x0/hexago/internal/shared/specification"
+	"gorm.io/gorm"
+)
+
+// errStopIteration stops the batches of Iterate when its caller breaks out.
+var errStopIteration = errors.New("stop iteration")
+
+// FindInBatches calls fn with the domains matching spec, batchSize at a time,
+// so that they are never all loaded in memory. The rows are ordered by primary
+// key and the orders of spec are ignored. A batchSize of 0 uses the batch size
+// of the repository. An error returned by fn stops the batches and is returned.
+func (r *Repository[A, D, E]) FindInBatches(
+	ctx context.Context,
+	spec specification.Specification,
+	batchSize int,
+	fn func(domains []*D) error,
+	scopes ...func(*gorm.DB) *gorm.DB,
+) error {
+	where, _, err := r.conditions(spec)
+	if err != nil {
+		return err
+	}
+
+	preload, err := r.preload()
+	if err != nil {
+		return err
+	}
+
+	if batchSize <= 0 {
+		batchSize = r.options.batchSize
+	}
+
+	var fnErr error
+	var entities []*E
+	err = r.Reader(ctx).Scopes(scopes...).Scopes(where, preload).FindInBatches(&entities, batchSize, func(*gorm.DB, int) error {
+		domains, err := r.adapter.ToDomains(entities)
+		if err == nil {
+			err = fn(domains)
+		}
+		fnErr = err
+		return err
+	}).Error
+	if fnErr != nil {
+		return fnErr
+	}
+	if err != nil {
+		return sharedErrors.NewDomainError(sharedErrors.System, err.Error())
+	}
+
+	return nil
+}
+
+// Iterate returns an iterator over the domains matching spec, which are loaded
+// in batches as FindInBatches does. An error ends the iteration after it is
+// yielded with a nil domain.
+func (r *Repository[A, D, E]) Iterate(
+	ctx context.Context,
+	spec specification.Specification,
+	scopes ...func(*gorm.DB) *gorm.DB,
+) iter.Seq2[*D, error] {
+	return func(yield func(*D, error) bool) {
+		err := r.FindInBatches(ctx, spec, 0, func(domains []*D) error {
+			for _, domain := range domains {
+				if !yield(domain, nil) {
+					return errStopIteration
+				}
+			}
+			return nil
+		}, scopes...)
+		if err != nil && !errors.Is(err, errStopIteration) {
+			yield(nil, err)
+		}
+	}
+}
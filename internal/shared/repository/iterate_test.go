@@ -0,0 +1,156 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/ming-0x0/hexago/internal/shared/specification"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestRepository_FindInBatches(t *testing.T) {
+	t.Parallel()
+
+	errStop := errors.New("stop")
+
+	tests := []struct {
+		name          string
+		adapterConfig DummyAdapter
+		fnErr         error
+		setupMock     func(sqlmock.Sqlmock)
+		assertion     assert.ErrorAssertionFunc
+		expected      [][]*DummyDomain
+	}{
+		{
+			name:          "Success",
+			adapterConfig: DummyAdapter{},
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta(
+					"SELECT * FROM `dummy_entities` WHERE `dummy_entities`.`name` = ? ORDER BY `dummy_entities`.`id` LIMIT ?",
+				)).WithArgs("Test", 2).WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "Test").AddRow(2, "Test"))
+				mock.ExpectQuery(regexp.QuoteMeta(
+					"SELECT * FROM `dummy_entities` WHERE `dummy_entities`.`id` > ? AND `dummy_entities`.`name` = ? ORDER BY `dummy_entities`.`id` LIMIT ?",
+				)).WithArgs(2, "Test", 2).WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(3, "Test"))
+			},
+			assertion: assert.NoError,
+			expected: [][]*DummyDomain{
+				{{ID: 1, Name: "Test"}, {ID: 2, Name: "Test"}},
+				{{ID: 3, Name: "Test"}},
+			},
+		},
+		{
+			name:          "Failure_FnError",
+			adapterConfig: DummyAdapter{},
+			fnErr:         errStop,
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SELECT (.+) FROM `dummy_entities`").
+					WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "Test").AddRow(2, "Test"))
+			},
+			assertion: func(t assert.TestingT, err error, _ ...any) bool {
+				return assert.ErrorIs(t, err, errStop)
+			},
+			expected: [][]*DummyDomain{
+				{{ID: 1, Name: "Test"}, {ID: 2, Name: "Test"}},
+			},
+		},
+		{
+			name:          "Failure_DBError",
+			adapterConfig: DummyAdapter{},
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SELECT (.+) FROM `dummy_entities`").WillReturnError(gorm.ErrInvalidField)
+			},
+			assertion: assert.Error,
+		},
+		{
+			name:          "Failure_AdapterToDomainsError",
+			adapterConfig: DummyAdapter{ShouldFailToDomains: true},
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SELECT (.+) FROM `dummy_entities`").
+					WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "Test"))
+			},
+			assertion: assert.Error,
+		},
+	}
+
+	for _, tt := range tests {
+		tc := tt
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			repo, mockedDB, _, sqlMock, _, _ := setupTest(t, tc.adapterConfig)
+			defer teardownTest(mockedDB)
+
+			tc.setupMock(sqlMock)
+			var batches [][]*DummyDomain
+			err := repo.FindInBatches(context.Background(), specification.Eq("name", "Test"), 2, func(domains []*DummyDomain) error {
+				batches = append(batches, domains)
+				return tc.fnErr
+			})
+			tc.assertion(t, err)
+			assert.Equal(t, tc.expected, batches)
+			assert.NoError(t, sqlMock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestRepository_Iterate(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Success", func(t *testing.T) {
+		t.Parallel()
+		repo, mockedDB, _, sqlMock, _, _ := setupTest(t, DummyAdapter{}, WithBatchSize(2))
+		defer teardownTest(mockedDB)
+
+		sqlMock.ExpectQuery(regexp.QuoteMeta(
+			"SELECT * FROM `dummy_entities` ORDER BY `dummy_entities`.`id` LIMIT ?",
+		)).WithArgs(2).WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "A").AddRow(2, "B"))
+		sqlMock.ExpectQuery(regexp.QuoteMeta(
+			"SELECT * FROM `dummy_entities` WHERE `dummy_entities`.`id` > ? ORDER BY `dummy_entities`.`id` LIMIT ?",
+		)).WithArgs(2, 2).WillReturnRows(sqlmock.NewRows([]string{"id", "name"}))
+
+		var got []*DummyDomain
+		for domain, err := range repo.Iterate(context.Background(), nil) {
+			assert.NoError(t, err)
+			got = append(got, domain)
+		}
+		assert.Equal(t, []*DummyDomain{{ID: 1, Name: "A"}, {ID: 2, Name: "B"}}, got)
+		assert.NoError(t, sqlMock.ExpectationsWereMet())
+	})
+
+	t.Run("Success_Break", func(t *testing.T) {
+		t.Parallel()
+		repo, mockedDB, _, sqlMock, _, _ := setupTest(t, DummyAdapter{}, WithBatchSize(2))
+		defer teardownTest(mockedDB)
+
+		sqlMock.ExpectQuery("SELECT (.+) FROM `dummy_entities`").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "A").AddRow(2, "B"))
+
+		var got []*DummyDomain
+		for domain, err := range repo.Iterate(context.Background(), nil) {
+			assert.NoError(t, err)
+			got = append(got, domain)
+			break
+		}
+		assert.Equal(t, []*DummyDomain{{ID: 1, Name: "A"}}, got)
+		assert.NoError(t, sqlMock.ExpectationsWereMet())
+	})
+
+	t.Run("Failure_DBError", func(t *testing.T) {
+		t.Parallel()
+		repo, mockedDB, _, sqlMock, _, _ := setupTest(t, DummyAdapter{})
+		defer teardownTest(mockedDB)
+
+		sqlMock.ExpectQuery("SELECT (.+) FROM `dummy_entities`").WillReturnError(gorm.ErrInvalidField)
+
+		var errs []error
+		for domain, err := range repo.Iterate(context.Background(), nil) {
+			assert.Nil(t, domain)
+			errs = append(errs, err)
+		}
+		assert.Len(t, errs, 1)
+		assert.Error(t, errs[0])
+	})
+}
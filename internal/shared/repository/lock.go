@@ -0,0 +1,115 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	sharedErrors "github.com/ming-0x0/hexago/internal/shared/errors"
+	"github.com/ming-0x0/hexago/internal/shared/specification"
+	"github.com/ming-0x0/hexago/internal/shared/transaction"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Lock is how a locking read treats the rows locked by other transactions.
+type Lock int
+
+const (
+	// LockWait waits until the rows are unlocked.
+	LockWait Lock = iota
+	// LockNoWait fails at once.
+	LockNoWait
+	// LockSkipLocked leaves the rows out of the result, so that concurrent
+	// workers each claim different rows.
+	LockSkipLocked
+)
+
+func (l Lock) clause() clause.Locking {
+	locking := clause.Locking{Strength: clause.LockingStrengthUpdate}
+	switch l {
+	case LockNoWait:
+		locking.Options = clause.LockingOptionsNoWait
+	case LockSkipLocked:
+		locking.Options = clause.LockingOptionsSkipLocked
+	}
+	return locking
+}
+
+// locking returns the transaction of ctx locking the rows it reads until it ends.
+func (r *Repository[A, D, E]) locking(ctx context.Context, lock Lock) (*gorm.DB, error) {
+	tx, ok := transaction.TransactionFromContext(ctx)
+	if !ok {
+		return nil, sharedErrors.NewDomainError(sharedErrors.BadRequest, "locking rows requires a transaction in the context")
+	}
+
+	return tx.Clauses(lock.clause()), nil
+}
+
+// TakeForUpdate returns the first domain matching spec, locking its row with
+// SELECT ... FOR UPDATE until the transaction of ctx ends. Its associations
+// are loaded without lock.
+func (r *Repository[A, D, E]) TakeForUpdate(
+	ctx context.Context,
+	spec specification.Specification,
+	lock Lock,
+	scopes ...func(*gorm.DB) *gorm.DB,
+) (*D, error) {
+	db, err := r.locking(ctx, lock)
+	if err != nil {
+		return nil, err
+	}
+
+	where, order, err := r.conditions(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	preload, err := r.preload()
+	if err != nil {
+		return nil, err
+	}
+
+	entity := new(E)
+	err = db.Scopes(scopes...).Scopes(where, order, preload).Take(entity).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, sharedErrors.NewDomainError(sharedErrors.NotFound, err.Error())
+		}
+
+		return nil, sharedErrors.NewDomainError(sharedErrors.System, err.Error())
+	}
+
+	return r.adapter.ToDomain(entity)
+}
+
+// FindForUpdate returns the domains matching spec, locking their rows with
+// SELECT ... FOR UPDATE until the transaction of ctx ends. Its associations
+// are loaded without lock.
+func (r *Repository[A, D, E]) FindForUpdate(
+	ctx context.Context,
+	spec specification.Specification,
+	lock Lock,
+	scopes ...func(*gorm.DB) *gorm.DB,
+) ([]*D, error) {
+	db, err := r.locking(ctx, lock)
+	if err != nil {
+		return nil, err
+	}
+
+	where, order, err := r.conditions(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	preload, err := r.preload()
+	if err != nil {
+		return nil, err
+	}
+
+	var entities []*E
+	if err := db.Scopes(scopes...).Scopes(where, order, preload).Find(&entities).Error; err != nil {
+		return nil, sharedErrors.NewDomainError(sharedErrors.System, err.Error())
+	}
+
+	return r.adapter.ToDomains(entities)
+}
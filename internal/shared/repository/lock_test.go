@@ -0,0 +1,197 @@
+package repository
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	sharedErrors "github.com/ming-0x0/hexago/internal/shared/errors"
+	"github.com/ming-0x0/hexago/internal/shared/specification"
+	"github.com/ming-0x0/hexago/internal/shared/transaction"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestRepository_FindForUpdate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		lock      Lock
+		inTx      bool
+		setupMock func(sqlmock.Sqlmock)
+		assertion assert.ErrorAssertionFunc
+		errCode   sharedErrors.ErrorCode
+		expected  []*DummyDomain
+	}{
+		{
+			name: "Success_Wait",
+			lock: LockWait,
+			inTx: true,
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(regexp.QuoteMeta(
+					"SELECT * FROM `dummy_entities` WHERE `dummy_entities`.`name` = ? LIMIT ? FOR UPDATE",
+				)).WithArgs("Test", 2).WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "Test"))
+				mock.ExpectCommit()
+			},
+			assertion: assert.NoError,
+			expected:  []*DummyDomain{{ID: 1, Name: "Test"}},
+		},
+		{
+			name: "Success_NoWait",
+			lock: LockNoWait,
+			inTx: true,
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(regexp.QuoteMeta(
+					"SELECT * FROM `dummy_entities` WHERE `dummy_entities`.`name` = ? LIMIT ? FOR UPDATE NOWAIT",
+				)).WithArgs("Test", 2).WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "Test"))
+				mock.ExpectCommit()
+			},
+			assertion: assert.NoError,
+			expected:  []*DummyDomain{{ID: 1, Name: "Test"}},
+		},
+		{
+			name: "Success_SkipLocked",
+			lock: LockSkipLocked,
+			inTx: true,
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(regexp.QuoteMeta(
+					"SELECT * FROM `dummy_entities` WHERE `dummy_entities`.`name` = ? LIMIT ? FOR UPDATE SKIP LOCKED",
+				)).WithArgs("Test", 2).WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(2, "Test"))
+				mock.ExpectCommit()
+			},
+			assertion: assert.NoError,
+			expected:  []*DummyDomain{{ID: 2, Name: "Test"}},
+		},
+		{
+			name:      "Failure_NoTransaction",
+			lock:      LockSkipLocked,
+			setupMock: func(mock sqlmock.Sqlmock) {},
+			assertion: assert.Error,
+			errCode:   sharedErrors.BadRequest,
+		},
+		{
+			name: "Failure_DBError",
+			lock: LockNoWait,
+			inTx: true,
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT (.+) FOR UPDATE NOWAIT").WillReturnError(gorm.ErrInvalidField)
+				mock.ExpectRollback()
+			},
+			assertion: assert.Error,
+			errCode:   sharedErrors.System,
+		},
+	}
+
+	for _, tt := range tests {
+		tc := tt
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			repo, mockedDB, gormDB, sqlMock, _, _ := setupTest(t, DummyAdapter{})
+			defer teardownTest(mockedDB)
+
+			tc.setupMock(sqlMock)
+			limit := func(db *gorm.DB) *gorm.DB { return db.Limit(2) }
+			find := func(ctx context.Context) ([]*DummyDomain, error) {
+				return repo.FindForUpdate(ctx, specification.Eq("name", "Test"), tc.lock, limit)
+			}
+
+			var got []*DummyDomain
+			var err error
+			if tc.inTx {
+				err = transaction.NewTransaction(gormDB).Do(context.Background(), func(ctx context.Context) error {
+					got, err = find(ctx)
+					return err
+				})
+			} else {
+				got, err = find(context.Background())
+			}
+			tc.assertion(t, err)
+			if err != nil {
+				var domainErr *sharedErrors.DomainError
+				assert.ErrorAs(t, err, &domainErr)
+				assert.Equal(t, tc.errCode, domainErr.ErrorCode())
+			}
+			assert.Equal(t, tc.expected, got)
+			assert.NoError(t, sqlMock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestRepository_TakeForUpdate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		inTx      bool
+		setupMock func(sqlmock.Sqlmock)
+		assertion assert.ErrorAssertionFunc
+		errCode   sharedErrors.ErrorCode
+		expected  *DummyDomain
+	}{
+		{
+			name: "Success",
+			inTx: true,
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(regexp.QuoteMeta(
+					"SELECT * FROM `dummy_entities` WHERE `dummy_entities`.`id` = ? LIMIT ? FOR UPDATE",
+				)).WithArgs(1, 1).WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "Test"))
+				mock.ExpectCommit()
+			},
+			assertion: assert.NoError,
+			expected:  &DummyDomain{ID: 1, Name: "Test"},
+		},
+		{
+			name: "Failure_NotFound",
+			inTx: true,
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery("SELECT (.+) FOR UPDATE").WillReturnError(gorm.ErrRecordNotFound)
+				mock.ExpectRollback()
+			},
+			assertion: assert.Error,
+			errCode:   sharedErrors.NotFound,
+		},
+		{
+			name:      "Failure_NoTransaction",
+			setupMock: func(mock sqlmock.Sqlmock) {},
+			assertion: assert.Error,
+			errCode:   sharedErrors.BadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		tc := tt
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			repo, mockedDB, gormDB, sqlMock, _, _ := setupTest(t, DummyAdapter{})
+			defer teardownTest(mockedDB)
+
+			tc.setupMock(sqlMock)
+			var got *DummyDomain
+			var err error
+			if tc.inTx {
+				err = transaction.NewTransaction(gormDB).Do(context.Background(), func(ctx context.Context) error {
+					got, err = repo.TakeForUpdate(ctx, specification.Eq("id", 1), LockWait)
+					return err
+				})
+			} else {
+				got, err = repo.TakeForUpdate(context.Background(), specification.Eq("id", 1), LockWait)
+			}
+			tc.assertion(t, err)
+			if err != nil {
+				var domainErr *sharedErrors.DomainError
+				assert.ErrorAs(t, err, &domainErr)
+				assert.Equal(t, tc.errCode, domainErr.ErrorCode())
+			}
+			assert.Equal(t, tc.expected, got)
+			assert.NoError(t, sqlMock.ExpectationsWereMet())
+		})
+	}
+}
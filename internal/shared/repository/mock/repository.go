@@ -11,9 +11,11 @@ package mock
 
 import (
 	context "context"
+	iter "iter"
 	reflect "reflect"
 
 	repository "github.com/ming-0x0/hexago/internal/shared/repository"
+	specification "github.com/ming-0x0/hexago/internal/shared/specification"
 	gomock "go.uber.org/mock/gomock"
 	gorm "gorm.io/gorm"
 )
@@ -42,6 +44,26 @@ func (m *MockRepositoryInterface[A, D, E]) EXPECT() *MockRepositoryInterfaceMock
 	return m.recorder
 }
 
+// Count mocks base method.
+func (m *MockRepositoryInterface[A, D, E]) Count(ctx context.Context, spec specification.Specification, scopes ...func(*gorm.DB) *gorm.DB) (int64, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, spec}
+	for _, a := range scopes {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Count", varargs...)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Count indicates an expected call of Count.
+func (mr *MockRepositoryInterfaceMockRecorder[A, D, E]) Count(ctx, spec any, scopes ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, spec}, scopes...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Count", reflect.TypeOf((*MockRepositoryInterface[A, D, E])(nil).Count), varargs...)
+}
+
 // Create mocks base method.
 func (m *MockRepositoryInterface[A, D, E]) Create(ctx context.Context, domain *D) error {
 	m.ctrl.T.Helper()
@@ -56,24 +78,58 @@ func (mr *MockRepositoryInterfaceMockRecorder[A, D, E]) Create(ctx, domain any)
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockRepositoryInterface[A, D, E])(nil).Create), ctx, domain)
 }
 
+// CreateMany mocks base method.
+func (m *MockRepositoryInterface[A, D, E]) CreateMany(ctx context.Context, domains []*D) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateMany", ctx, domains)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateMany indicates an expected call of CreateMany.
+func (mr *MockRepositoryInterfaceMockRecorder[A, D, E]) CreateMany(ctx, domains any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateMany", reflect.TypeOf((*MockRepositoryInterface[A, D, E])(nil).CreateMany), ctx, domains)
+}
+
 // DeleteByConditions mocks base method.
-func (m *MockRepositoryInterface[A, D, E]) DeleteByConditions(ctx context.Context, conditions map[string]any) error {
+func (m *MockRepositoryInterface[A, D, E]) DeleteByConditions(ctx context.Context, spec specification.Specification) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteByConditions", ctx, conditions)
+	ret := m.ctrl.Call(m, "DeleteByConditions", ctx, spec)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // DeleteByConditions indicates an expected call of DeleteByConditions.
-func (mr *MockRepositoryInterfaceMockRecorder[A, D, E]) DeleteByConditions(ctx, conditions any) *gomock.Call {
+func (mr *MockRepositoryInterfaceMockRecorder[A, D, E]) DeleteByConditions(ctx, spec any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteByConditions", reflect.TypeOf((*MockRepositoryInterface[A, D, E])(nil).DeleteByConditions), ctx, conditions)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteByConditions", reflect.TypeOf((*MockRepositoryInterface[A, D, E])(nil).DeleteByConditions), ctx, spec)
+}
+
+// Exists mocks base method.
+func (m *MockRepositoryInterface[A, D, E]) Exists(ctx context.Context, spec specification.Specification, scopes ...func(*gorm.DB) *gorm.DB) (bool, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, spec}
+	for _, a := range scopes {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Exists", varargs...)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Exists indicates an expected call of Exists.
+func (mr *MockRepositoryInterfaceMockRecorder[A, D, E]) Exists(ctx, spec any, scopes ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, spec}, scopes...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Exists", reflect.TypeOf((*MockRepositoryInterface[A, D, E])(nil).Exists), varargs...)
 }
 
 // FindByConditions mocks base method.
-func (m *MockRepositoryInterface[A, D, E]) FindByConditions(ctx context.Context, conditions map[string]any, scopes ...func(*gorm.DB) *gorm.DB) ([]*D, error) {
+func (m *MockRepositoryInterface[A, D, E]) FindByConditions(ctx context.Context, spec specification.Specification, scopes ...func(*gorm.DB) *gorm.DB) ([]*D, error) {
 	m.ctrl.T.Helper()
-	varargs := []any{ctx, conditions}
+	varargs := []any{ctx, spec}
 	for _, a := range scopes {
 		varargs = append(varargs, a)
 	}
@@ -84,33 +140,197 @@ func (m *MockRepositoryInterface[A, D, E]) FindByConditions(ctx context.Context,
 }
 
 // FindByConditions indicates an expected call of FindByConditions.
-func (mr *MockRepositoryInterfaceMockRecorder[A, D, E]) FindByConditions(ctx, conditions any, scopes ...any) *gomock.Call {
+func (mr *MockRepositoryInterfaceMockRecorder[A, D, E]) FindByConditions(ctx, spec any, scopes ...any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	varargs := append([]any{ctx, conditions}, scopes...)
+	varargs := append([]any{ctx, spec}, scopes...)
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByConditions", reflect.TypeOf((*MockRepositoryInterface[A, D, E])(nil).FindByConditions), varargs...)
 }
 
+// FindByConditionsWithCursor mocks base method.
+func (m *MockRepositoryInterface[A, D, E]) FindByConditionsWithCursor(ctx context.Context, page repository.CursorPage, spec specification.Specification, scopes ...func(*gorm.DB) *gorm.DB) (repository.CursorResult[D], error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, page, spec}
+	for _, a := range scopes {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "FindByConditionsWithCursor", varargs...)
+	ret0, _ := ret[0].(repository.CursorResult[D])
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByConditionsWithCursor indicates an expected call of FindByConditionsWithCursor.
+func (mr *MockRepositoryInterfaceMockRecorder[A, D, E]) FindByConditionsWithCursor(ctx, page, spec any, scopes ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, page, spec}, scopes...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByConditionsWithCursor", reflect.TypeOf((*MockRepositoryInterface[A, D, E])(nil).FindByConditionsWithCursor), varargs...)
+}
+
 // FindByConditionsWithPagination mocks base method.
-func (m *MockRepositoryInterface[A, D, E]) FindByConditionsWithPagination(ctx context.Context, pageData map[string]int, conditions map[string]any, scopes ...func(*gorm.DB) *gorm.DB) ([]*D, int64, error) {
+func (m *MockRepositoryInterface[A, D, E]) FindByConditionsWithPagination(ctx context.Context, page repository.Page, spec specification.Specification, scopes ...func(*gorm.DB) *gorm.DB) (repository.PageResult[D], error) {
 	m.ctrl.T.Helper()
-	varargs := []any{ctx, pageData, conditions}
+	varargs := []any{ctx, page, spec}
 	for _, a := range scopes {
 		varargs = append(varargs, a)
 	}
 	ret := m.ctrl.Call(m, "FindByConditionsWithPagination", varargs...)
-	ret0, _ := ret[0].([]*D)
-	ret1, _ := ret[1].(int64)
-	ret2, _ := ret[2].(error)
-	return ret0, ret1, ret2
+	ret0, _ := ret[0].(repository.PageResult[D])
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // FindByConditionsWithPagination indicates an expected call of FindByConditionsWithPagination.
-func (mr *MockRepositoryInterfaceMockRecorder[A, D, E]) FindByConditionsWithPagination(ctx, pageData, conditions any, scopes ...any) *gomock.Call {
+func (mr *MockRepositoryInterfaceMockRecorder[A, D, E]) FindByConditionsWithPagination(ctx, page, spec any, scopes ...any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	varargs := append([]any{ctx, pageData, conditions}, scopes...)
+	varargs := append([]any{ctx, page, spec}, scopes...)
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByConditionsWithPagination", reflect.TypeOf((*MockRepositoryInterface[A, D, E])(nil).FindByConditionsWithPagination), varargs...)
 }
 
+// FindForUpdate mocks base method.
+func (m *MockRepositoryInterface[A, D, E]) FindForUpdate(ctx context.Context, spec specification.Specification, lock repository.Lock, scopes ...func(*gorm.DB) *gorm.DB) ([]*D, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, spec, lock}
+	for _, a := range scopes {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "FindForUpdate", varargs...)
+	ret0, _ := ret[0].([]*D)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindForUpdate indicates an expected call of FindForUpdate.
+func (mr *MockRepositoryInterfaceMockRecorder[A, D, E]) FindForUpdate(ctx, spec, lock any, scopes ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, spec, lock}, scopes...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindForUpdate", reflect.TypeOf((*MockRepositoryInterface[A, D, E])(nil).FindForUpdate), varargs...)
+}
+
+// FindInBatches mocks base method.
+func (m *MockRepositoryInterface[A, D, E]) FindInBatches(ctx context.Context, spec specification.Specification, batchSize int, fn func([]*D) error, scopes ...func(*gorm.DB) *gorm.DB) error {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, spec, batchSize, fn}
+	for _, a := range scopes {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "FindInBatches", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// FindInBatches indicates an expected call of FindInBatches.
+func (mr *MockRepositoryInterfaceMockRecorder[A, D, E]) FindInBatches(ctx, spec, batchSize, fn any, scopes ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, spec, batchSize, fn}, scopes...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindInBatches", reflect.TypeOf((*MockRepositoryInterface[A, D, E])(nil).FindInBatches), varargs...)
+}
+
+// ForceDelete mocks base method.
+func (m *MockRepositoryInterface[A, D, E]) ForceDelete(ctx context.Context, spec specification.Specification) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ForceDelete", ctx, spec)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ForceDelete indicates an expected call of ForceDelete.
+func (mr *MockRepositoryInterfaceMockRecorder[A, D, E]) ForceDelete(ctx, spec any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ForceDelete", reflect.TypeOf((*MockRepositoryInterface[A, D, E])(nil).ForceDelete), ctx, spec)
+}
+
+// GroupCount mocks base method.
+func (m *MockRepositoryInterface[A, D, E]) GroupCount(ctx context.Context, column string, spec specification.Specification, scopes ...func(*gorm.DB) *gorm.DB) (map[string]int64, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, column, spec}
+	for _, a := range scopes {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GroupCount", varargs...)
+	ret0, _ := ret[0].(map[string]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GroupCount indicates an expected call of GroupCount.
+func (mr *MockRepositoryInterfaceMockRecorder[A, D, E]) GroupCount(ctx, column, spec any, scopes ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, column, spec}, scopes...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GroupCount", reflect.TypeOf((*MockRepositoryInterface[A, D, E])(nil).GroupCount), varargs...)
+}
+
+// Iterate mocks base method.
+func (m *MockRepositoryInterface[A, D, E]) Iterate(ctx context.Context, spec specification.Specification, scopes ...func(*gorm.DB) *gorm.DB) iter.Seq2[*D, error] {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, spec}
+	for _, a := range scopes {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Iterate", varargs...)
+	ret0, _ := ret[0].(iter.Seq2[*D, error])
+	return ret0
+}
+
+// Iterate indicates an expected call of Iterate.
+func (mr *MockRepositoryInterfaceMockRecorder[A, D, E]) Iterate(ctx, spec any, scopes ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, spec}, scopes...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Iterate", reflect.TypeOf((*MockRepositoryInterface[A, D, E])(nil).Iterate), varargs...)
+}
+
+// Project mocks base method.
+func (m *MockRepositoryInterface[A, D, E]) Project(ctx context.Context, dest any, spec specification.Specification, scopes ...func(*gorm.DB) *gorm.DB) error {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, dest, spec}
+	for _, a := range scopes {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Project", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Project indicates an expected call of Project.
+func (mr *MockRepositoryInterfaceMockRecorder[A, D, E]) Project(ctx, dest, spec any, scopes ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, dest, spec}, scopes...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Project", reflect.TypeOf((*MockRepositoryInterface[A, D, E])(nil).Project), varargs...)
+}
+
+// ProjectWithPagination mocks base method.
+func (m *MockRepositoryInterface[A, D, E]) ProjectWithPagination(ctx context.Context, dest any, page repository.Page, spec specification.Specification, scopes ...func(*gorm.DB) *gorm.DB) (int64, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, dest, page, spec}
+	for _, a := range scopes {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ProjectWithPagination", varargs...)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ProjectWithPagination indicates an expected call of ProjectWithPagination.
+func (mr *MockRepositoryInterfaceMockRecorder[A, D, E]) ProjectWithPagination(ctx, dest, page, spec any, scopes ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, dest, page, spec}, scopes...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProjectWithPagination", reflect.TypeOf((*MockRepositoryInterface[A, D, E])(nil).ProjectWithPagination), varargs...)
+}
+
+// Restore mocks base method.
+func (m *MockRepositoryInterface[A, D, E]) Restore(ctx context.Context, spec specification.Specification) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Restore", ctx, spec)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Restore indicates an expected call of Restore.
+func (mr *MockRepositoryInterfaceMockRecorder[A, D, E]) Restore(ctx, spec any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockRepositoryInterface[A, D, E])(nil).Restore), ctx, spec)
+}
+
 // Save mocks base method.
 func (m *MockRepositoryInterface[A, D, E]) Save(ctx context.Context, domain *D) error {
 	m.ctrl.T.Helper()
@@ -125,10 +345,24 @@ func (mr *MockRepositoryInterfaceMockRecorder[A, D, E]) Save(ctx, domain any) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockRepositoryInterface[A, D, E])(nil).Save), ctx, domain)
 }
 
+// SaveMany mocks base method.
+func (m *MockRepositoryInterface[A, D, E]) SaveMany(ctx context.Context, domains []*D) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveMany", ctx, domains)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveMany indicates an expected call of SaveMany.
+func (mr *MockRepositoryInterfaceMockRecorder[A, D, E]) SaveMany(ctx, domains any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveMany", reflect.TypeOf((*MockRepositoryInterface[A, D, E])(nil).SaveMany), ctx, domains)
+}
+
 // TakeByConditions mocks base method.
-func (m *MockRepositoryInterface[A, D, E]) TakeByConditions(ctx context.Context, conditions map[string]any, scopes ...func(*gorm.DB) *gorm.DB) (*D, error) {
+func (m *MockRepositoryInterface[A, D, E]) TakeByConditions(ctx context.Context, spec specification.Specification, scopes ...func(*gorm.DB) *gorm.DB) (*D, error) {
 	m.ctrl.T.Helper()
-	varargs := []any{ctx, conditions}
+	varargs := []any{ctx, spec}
 	for _, a := range scopes {
 		varargs = append(varargs, a)
 	}
@@ -139,8 +373,42 @@ func (m *MockRepositoryInterface[A, D, E]) TakeByConditions(ctx context.Context,
 }
 
 // TakeByConditions indicates an expected call of TakeByConditions.
-func (mr *MockRepositoryInterfaceMockRecorder[A, D, E]) TakeByConditions(ctx, conditions any, scopes ...any) *gomock.Call {
+func (mr *MockRepositoryInterfaceMockRecorder[A, D, E]) TakeByConditions(ctx, spec any, scopes ...any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	varargs := append([]any{ctx, conditions}, scopes...)
+	varargs := append([]any{ctx, spec}, scopes...)
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TakeByConditions", reflect.TypeOf((*MockRepositoryInterface[A, D, E])(nil).TakeByConditions), varargs...)
 }
+
+// TakeForUpdate mocks base method.
+func (m *MockRepositoryInterface[A, D, E]) TakeForUpdate(ctx context.Context, spec specification.Specification, lock repository.Lock, scopes ...func(*gorm.DB) *gorm.DB) (*D, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, spec, lock}
+	for _, a := range scopes {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "TakeForUpdate", varargs...)
+	ret0, _ := ret[0].(*D)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TakeForUpdate indicates an expected call of TakeForUpdate.
+func (mr *MockRepositoryInterfaceMockRecorder[A, D, E]) TakeForUpdate(ctx, spec, lock any, scopes ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, spec, lock}, scopes...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TakeForUpdate", reflect.TypeOf((*MockRepositoryInterface[A, D, E])(nil).TakeForUpdate), varargs...)
+}
+
+// Upsert mocks base method.
+func (m *MockRepositoryInterface[A, D, E]) Upsert(ctx context.Context, domains []*D, onConflict repository.OnConflict) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Upsert", ctx, domains, onConflict)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Upsert indicates an expected call of Upsert.
+func (mr *MockRepositoryInterfaceMockRecorder[A, D, E]) Upsert(ctx, domains, onConflict any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Upsert", reflect.TypeOf((*MockRepositoryInterface[A, D, E])(nil).Upsert), ctx, domains, onConflict)
+}
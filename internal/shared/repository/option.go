@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"github.com/ming-0x0/hexago/internal/shared/audit"
+	"gorm.io/gorm"
+)
+
+const DefaultBatchSize = 100
+
+type options struct {
+	batchSize int
+	recorder  audit.RecorderInterface
+	replicas  []*gorm.DB
+	// associations are the names of the association fields of the entity.
+	associations []string
+}
+
+// Option configures a Repository.
+type Option func(*options)
+
+// WithBatchSize sets the number of rows written per statement by CreateMany,
+// SaveMany and Upsert, and read per query by Iterate. default: DefaultBatchSize
+func WithBatchSize(batchSize int) Option {
+	return func(o *options) {
+		if batchSize > 0 {
+			o.batchSize = batchSize
+		}
+	}
+}
+
+// WithRecorder records an audit log of the changes made by Create, Save and
+// DeleteByConditions. Each write and its log are made in one transaction.
+func WithRecorder(recorder audit.RecorderInterface) Option {
+	return func(o *options) {
+		o.recorder = recorder
+	}
+}
+
+// WithReplicas routes the reads outside a transaction to the replicas in
+// turn, unless their context is from WithPrimary. Writes go to the primary.
+func WithReplicas(replicas ...*gorm.DB) Option {
+	return func(o *options) {
+		o.replicas = append(o.replicas, replicas...)
+	}
+}
+
+// WithAssociations loads the has one and has many associations of the entity
+// named by their field along with it. Create and Save write them as a unit
+// with the entity, deleting the stored children the entity no longer has; a
+// has one field has no child while it is nil or zero. The other writes ignore
+// them.
+func WithAssociations(names ...string) Option {
+	return func(o *options) {
+		o.associations = append(o.associations, names...)
+	}
+}
+
+func newOptions(opts []Option) options {
+	o := options{
+		batchSize: DefaultBatchSize,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
@@ -0,0 +1,119 @@
+package repository
+
+import (
+	"fmt"
+	"slices"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	sharedEntity "github.com/ming-0x0/hexago/internal/shared/entity"
+	sharedErrors "github.com/ming-0x0/hexago/internal/shared/errors"
+	"github.com/ming-0x0/hexago/internal/shared/specification"
+	"gorm.io/gorm/schema"
+)
+
+const (
+	DefaultPage    = 1
+	DefaultPerPage = 20
+	MaxPerPage     = 100
+)
+
+// Page requests a page of rows by page number.
+type Page struct {
+	// Page is the 1-based page number. default: DefaultPage
+	Page int `json:"page"`
+	// PerPage is the number of rows of a page, at most MaxPerPage. default: DefaultPerPage
+	PerPage int `json:"per_page"`
+	// Sort orders the rows by sortable columns of the entity, before the
+	// orders of the specification.
+	Sort []specification.Order `json:"sort"`
+}
+
+func NewPage(page, perPage int) (Page, error) {
+	p := Page{
+		Page:    page,
+		PerPage: perPage,
+	}
+
+	if err := p.Validate(); err != nil {
+		return Page{}, err
+	}
+
+	return p, nil
+}
+
+func (p Page) Validate() error {
+	err := validation.ValidateStruct(&p,
+		validation.Field(&p.Page, validation.Min(0)),
+		validation.Field(&p.PerPage, validation.Min(0), validation.Max(MaxPerPage)),
+	)
+	if err != nil {
+		return sharedErrors.NewDomainError(sharedErrors.Validation, err.Error())
+	}
+
+	return nil
+}
+
+// withDefaults returns p with its zero values replaced by the defaults.
+func (p Page) withDefaults() Page {
+	if p.Page <= 0 {
+		p.Page = DefaultPage
+	}
+	if p.PerPage <= 0 {
+		p.PerPage = DefaultPerPage
+	}
+	return p
+}
+
+// pageOrders returns the orders of page: page.Sort, whose columns must be
+// sortable columns of the entity, then the orders of spec, then the primary
+// key, which makes the page boundaries deterministic.
+func (r *Repository[A, D, E]) pageOrders(
+	s *schema.Schema,
+	page Page,
+	spec specification.Specification,
+) ([]specification.Order, error) {
+	if len(page.Sort) > 0 {
+		var sortable []string
+		if e, ok := any(new(E)).(sharedEntity.SortableInterface); ok {
+			sortable = e.SortableColumns()
+		}
+		for _, order := range page.Sort {
+			if !slices.Contains(sortable, order.Column) {
+				return nil, sharedErrors.NewDomainError(sharedErrors.Validation, fmt.Sprintf("column %q of table %s is not sortable", order.Column, s.Table))
+			}
+		}
+	}
+
+	orders := slices.Concat(page.Sort, specification.Orders(spec))
+	if pk := s.PrioritizedPrimaryField; pk != nil {
+		if !slices.ContainsFunc(orders, func(o specification.Order) bool { return o.Column == pk.DBName }) {
+			orders = append(orders, specification.Asc(pk.DBName))
+		}
+	}
+
+	return orders, nil
+}
+
+// PageResult is a page of domains with the total number of matching rows.
+type PageResult[D any] struct {
+	Items      []*D  `json:"items"`
+	Total      int64 `json:"total"`
+	Page       int   `json:"page"`
+	PerPage    int   `json:"per_page"`
+	TotalPages int   `json:"total_pages"`
+	HasNext    bool  `json:"has_next"`
+}
+
+func NewPageResult[D any](items []*D, total int64, page Page) PageResult[D] {
+	page = page.withDefaults()
+	totalPages := int((total + int64(page.PerPage) - 1) / int64(page.PerPage))
+
+	return PageResult[D]{
+		Items:      items,
+		Total:      total,
+		Page:       page.Page,
+		PerPage:    page.PerPage,
+		TotalPages: totalPages,
+		HasNext:    page.Page < totalPages,
+	}
+}
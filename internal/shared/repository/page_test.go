@@ -0,0 +1,241 @@
+package repository
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/ming-0x0/hexago/internal/shared/specification"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewPage(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		page      int
+		perPage   int
+		assertion assert.ErrorAssertionFunc
+		expected  Page
+	}{
+		{
+			name:      "Success",
+			page:      2,
+			perPage:   50,
+			assertion: assert.NoError,
+			expected:  Page{Page: 2, PerPage: 50},
+		},
+		{
+			name:      "Success_ZeroUsesDefaults",
+			page:      0,
+			perPage:   0,
+			assertion: assert.NoError,
+			expected:  Page{},
+		},
+		{
+			name:      "Success_MaxPerPage",
+			page:      1,
+			perPage:   MaxPerPage,
+			assertion: assert.NoError,
+			expected:  Page{Page: 1, PerPage: MaxPerPage},
+		},
+		{
+			name:      "Failure_PerPageTooLarge",
+			page:      1,
+			perPage:   100000,
+			assertion: assert.Error,
+		},
+		{
+			name:      "Failure_NegativePage",
+			page:      -1,
+			perPage:   10,
+			assertion: assert.Error,
+		},
+		{
+			name:      "Failure_NegativePerPage",
+			page:      1,
+			perPage:   -10,
+			assertion: assert.Error,
+		},
+	}
+
+	for _, tt := range tests {
+		tc := tt
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			page, err := NewPage(tc.page, tc.perPage)
+			tc.assertion(t, err)
+			assert.Equal(t, tc.expected, page)
+		})
+	}
+}
+
+func TestNewPageResult(t *testing.T) {
+	t.Parallel()
+
+	items := []*DummyDomain{{ID: 1, Name: "Test1"}}
+
+	tests := []struct {
+		name     string
+		total    int64
+		page     Page
+		expected PageResult[DummyDomain]
+	}{
+		{
+			name:     "FirstOfSeveralPages",
+			total:    21,
+			page:     Page{Page: 1, PerPage: 10},
+			expected: PageResult[DummyDomain]{Items: items, Total: 21, Page: 1, PerPage: 10, TotalPages: 3, HasNext: true},
+		},
+		{
+			name:     "LastPage",
+			total:    20,
+			page:     Page{Page: 2, PerPage: 10},
+			expected: PageResult[DummyDomain]{Items: items, Total: 20, Page: 2, PerPage: 10, TotalPages: 2, HasNext: false},
+		},
+		{
+			name:     "Defaults",
+			total:    1,
+			page:     Page{},
+			expected: PageResult[DummyDomain]{Items: items, Total: 1, Page: DefaultPage, PerPage: DefaultPerPage, TotalPages: 1, HasNext: false},
+		},
+		{
+			name:     "NoRows",
+			total:    0,
+			page:     Page{Page: 1, PerPage: 10},
+			expected: PageResult[DummyDomain]{Items: items, Total: 0, Page: 1, PerPage: 10, TotalPages: 0, HasNext: false},
+		},
+	}
+
+	for _, tt := range tests {
+		tc := tt
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tc.expected, NewPageResult(items, tc.total, tc.page))
+		})
+	}
+}
+
+func TestRepository_FindByConditionsWithPagination_Sort(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		page      Page
+		spec      specification.Specification
+		query     string
+		assertion assert.ErrorAssertionFunc
+	}{
+		{
+			name:      "DefaultOrderByPrimaryKey",
+			page:      Page{},
+			query:     "SELECT * FROM `dummy_entities` ORDER BY `dummy_entities`.`id` LIMIT ?",
+			assertion: assert.NoError,
+		},
+		{
+			name:      "Sort",
+			page:      Page{Sort: []specification.Order{specification.Desc("name")}},
+			query:     "SELECT * FROM `dummy_entities` ORDER BY `dummy_entities`.`name` DESC,`dummy_entities`.`id` LIMIT ?",
+			assertion: assert.NoError,
+		},
+		{
+			name:      "SortBeforeSpecOrders",
+			page:      Page{Sort: specification.ParseOrders("name")},
+			spec:      specification.OrderBy(nil, specification.Desc("id")),
+			query:     "SELECT * FROM `dummy_entities` ORDER BY `dummy_entities`.`name`,`dummy_entities`.`id` DESC LIMIT ?",
+			assertion: assert.NoError,
+		},
+		{
+			name:      "Failure_NotSortable",
+			page:      Page{Sort: []specification.Order{specification.Asc("id")}},
+			assertion: assert.Error,
+		},
+		{
+			name:      "Failure_InjectedSort",
+			page:      Page{Sort: specification.ParseOrders("name; DROP TABLE dummy_entities")},
+			assertion: assert.Error,
+		},
+	}
+
+	for _, tt := range tests {
+		tc := tt
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			repo, mockedDB, _, sqlMock, _, _ := setupTest(t, DummyAdapter{})
+			defer teardownTest(mockedDB)
+
+			if tc.query != "" {
+				sqlMock.ExpectQuery("SELECT count").WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+				sqlMock.ExpectQuery(regexp.QuoteMeta(tc.query)).
+					WithArgs(DefaultPerPage).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "Test"))
+			}
+
+			_, err := repo.FindByConditionsWithPagination(context.Background(), tc.page, tc.spec)
+			tc.assertion(t, err)
+			assert.NoError(t, sqlMock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestPageProjection(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Success_SkipsValidatingAdapter", func(t *testing.T) {
+		t.Parallel()
+		repo, mockedDB, _, sqlMock, _, _ := setupTest(t, DummyAdapter{ValidatesName: true})
+		defer teardownTest(mockedDB)
+
+		sqlMock.ExpectQuery(regexp.QuoteMeta("SELECT count(*) FROM `dummy_entities`")).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+		sqlMock.ExpectQuery(regexp.QuoteMeta(
+			"SELECT `dummy_entities`.`id` FROM `dummy_entities` ORDER BY `dummy_entities`.`name` DESC,`dummy_entities`.`id` LIMIT ? OFFSET ?",
+		)).
+			WithArgs(2, 2).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+
+		page := Page{Page: 2, PerPage: 2, Sort: []specification.Order{specification.Desc("name")}}
+		got, err := PageProjection[DummyID](context.Background(), repo, page, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, NewPageResult([]*DummyID{{ID: 1}}, 3, page), got)
+		assert.NoError(t, sqlMock.ExpectationsWereMet())
+	})
+
+	t.Run("Success_ValidatingAdapterLoadsWholeRows", func(t *testing.T) {
+		t.Parallel()
+		repo, mockedDB, _, sqlMock, _, _ := setupTest(t, DummyAdapter{ValidatesName: true})
+		defer teardownTest(mockedDB)
+
+		sqlMock.ExpectQuery("SELECT count").WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+		sqlMock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `dummy_entities` ORDER BY `dummy_entities`.`id` LIMIT ?")).
+			WithArgs(DefaultPerPage).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "Test"))
+
+		got, err := repo.FindByConditionsWithPagination(context.Background(), Page{}, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, []*DummyDomain{{ID: 1, Name: "Test"}}, got.Items)
+		assert.NoError(t, sqlMock.ExpectationsWereMet())
+	})
+
+	t.Run("Failure_UnknownColumn", func(t *testing.T) {
+		t.Parallel()
+		repo, mockedDB, _, sqlMock, _, _ := setupTest(t, DummyAdapter{ValidatesName: true})
+		defer teardownTest(mockedDB)
+
+		_, err := PageProjection[DummyUnknown](context.Background(), repo, Page{}, nil)
+		assert.Error(t, err)
+		assert.NoError(t, sqlMock.ExpectationsWereMet())
+	})
+
+	t.Run("Failure_InvalidPage", func(t *testing.T) {
+		t.Parallel()
+		repo, mockedDB, _, sqlMock, _, _ := setupTest(t, DummyAdapter{ValidatesName: true})
+		defer teardownTest(mockedDB)
+
+		_, err := PageProjection[DummyName](context.Background(), repo, Page{PerPage: MaxPerPage + 1}, nil)
+		assert.Error(t, err)
+		assert.NoError(t, sqlMock.ExpectationsWereMet())
+	})
+}
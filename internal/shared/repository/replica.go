@@ -0,0 +1,36 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/ming-0x0/hexago/internal/shared/transaction"
+	"gorm.io/gorm"
+)
+
+// primaryCtxKey is the context key set by WithPrimary.
+type primaryCtxKey struct{}
+
+// WithPrimary returns a copy of ctx whose reads go to the primary instead of
+// the replicas, so that they see the writes made just before.
+func WithPrimary(ctx context.Context) context.Context {
+	return context.WithValue(ctx, primaryCtxKey{}, true)
+}
+
+func primaryFromContext(ctx context.Context) bool {
+	primary, _ := ctx.Value(primaryCtxKey{}).(bool)
+	return primary
+}
+
+// Reader returns the database of the reads of ctx: the transaction of ctx if
+// any, the primary if ctx requires it, otherwise the next replica in turn.
+func (r *Repository[A, D, E]) Reader(ctx context.Context) *gorm.DB {
+	if len(r.options.replicas) == 0 || primaryFromContext(ctx) {
+		return r.DB(ctx)
+	}
+	if tx, ok := transaction.TransactionFromContext(ctx); ok {
+		return tx
+	}
+
+	n := r.next.Add(1) - 1
+	return r.options.replicas[n%uint64(len(r.options.replicas))].WithContext(ctx)
+}
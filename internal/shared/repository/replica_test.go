@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/ming-0x0/hexago/internal/shared/dbmocker"
+	"github.com/ming-0x0/hexago/internal/shared/specification"
+	"github.com/ming-0x0/hexago/internal/shared/transaction"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupReplicaTest(t *testing.T) (*Repository[*DummyAdapter, DummyDomain, DummyEntity], []*dbmocker.MockedRepository) {
+	var mockedDBs []*dbmocker.MockedRepository
+	for range 3 {
+		mockedDB, err := dbmocker.NewMockedDB()
+		if err != nil {
+			t.Fatalf("error when creating mock DB: %v", err)
+		}
+		mockedDBs = append(mockedDBs, mockedDB)
+	}
+
+	primary, replicas := mockedDBs[0], mockedDBs[1:]
+	repo := NewRepository(primary.GormDB, primary.Logger, &DummyAdapter{}, WithReplicas(replicas[0].GormDB, replicas[1].GormDB))
+	return repo, mockedDBs
+}
+
+func expectFind(mock sqlmock.Sqlmock) {
+	mock.ExpectQuery(regexp.QuoteMeta(
+		"SELECT * FROM `dummy_entities` WHERE `dummy_entities`.`id` = ?",
+	)).WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "Test"))
+}
+
+func TestRepository_Reader(t *testing.T) {
+	t.Parallel()
+
+	const primary, replica1, replica2 = 0, 1, 2
+
+	tests := []struct {
+		name      string
+		call      func(*Repository[*DummyAdapter, DummyDomain, DummyEntity]) error
+		setupMock func(mocks []sqlmock.Sqlmock)
+	}{
+		{
+			name: "RoundRobinReplicas",
+			call: func(repo *Repository[*DummyAdapter, DummyDomain, DummyEntity]) error {
+				for range 3 {
+					if _, err := repo.FindByConditions(context.Background(), specification.Eq("id", 1)); err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+			setupMock: func(mocks []sqlmock.Sqlmock) {
+				expectFind(mocks[replica1])
+				expectFind(mocks[replica2])
+				expectFind(mocks[replica1])
+			},
+		},
+		{
+			name: "WithPrimary",
+			call: func(repo *Repository[*DummyAdapter, DummyDomain, DummyEntity]) error {
+				_, err := repo.FindByConditions(WithPrimary(context.Background()), specification.Eq("id", 1))
+				return err
+			},
+			setupMock: func(mocks []sqlmock.Sqlmock) {
+				expectFind(mocks[primary])
+			},
+		},
+		{
+			name: "Transaction",
+			call: func(repo *Repository[*DummyAdapter, DummyDomain, DummyEntity]) error {
+				return transaction.NewTransaction(repo.db).Do(context.Background(), func(ctx context.Context) error {
+					_, err := repo.FindByConditions(ctx, specification.Eq("id", 1))
+					return err
+				})
+			},
+			setupMock: func(mocks []sqlmock.Sqlmock) {
+				mocks[primary].ExpectBegin()
+				expectFind(mocks[primary])
+				mocks[primary].ExpectCommit()
+			},
+		},
+		{
+			name: "Write",
+			call: func(repo *Repository[*DummyAdapter, DummyDomain, DummyEntity]) error {
+				return repo.Save(context.Background(), &DummyDomain{ID: 1, Name: "Test"})
+			},
+			setupMock: func(mocks []sqlmock.Sqlmock) {
+				mocks[primary].ExpectBegin()
+				mocks[primary].ExpectExec("UPDATE `dummy_entities`").WillReturnResult(sqlmock.NewResult(0, 1))
+				mocks[primary].ExpectCommit()
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tc := tt
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			repo, mockedDBs := setupReplicaTest(t)
+			var mocks []sqlmock.Sqlmock
+			for _, mockedDB := range mockedDBs {
+				defer teardownTest(mockedDB)
+				mocks = append(mocks, mockedDB.SqlMock)
+			}
+
+			tc.setupMock(mocks)
+			assert.NoError(t, tc.call(repo))
+			for _, mock := range mocks {
+				assert.NoError(t, mock.ExpectationsWereMet())
+			}
+		})
+	}
+}
@@ -3,16 +3,20 @@ package repository
 import (
 	"context"
 	"errors"
+	"fmt"
+	"iter"
+	"reflect"
+	"sync/atomic"
 
+	"github.com/ming-0x0/hexago/internal/shared/audit"
+	sharedEntity "github.com/ming-0x0/hexago/internal/shared/entity"
 	sharedErrors "github.com/ming-0x0/hexago/internal/shared/errors"
+	"github.com/ming-0x0/hexago/internal/shared/specification"
 	"github.com/ming-0x0/hexago/internal/shared/transaction"
 	"github.com/sirupsen/logrus"
 	"gorm.io/gorm"
-)
-
-const (
-	DefaultPage    = 1
-	DefaultPerPage = 20
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
 )
 
 type AdapterInterface[D, E any] interface {
@@ -34,12 +38,12 @@ type RepositoryInterface[A AdapterInterface[D, E], D, E any] interface {
 	) error
 	FindByConditions(
 		ctx context.Context,
-		conditions map[string]any,
+		spec specification.Specification,
 		scopes ...func(*gorm.DB) *gorm.DB,
 	) ([]*D, error)
 	TakeByConditions(
 		ctx context.Context,
-		conditions map[string]any,
+		spec specification.Specification,
 		scopes ...func(*gorm.DB) *gorm.DB,
 	) (*D, error)
 	Save(
@@ -48,31 +52,116 @@ type RepositoryInterface[A AdapterInterface[D, E], D, E any] interface {
 	) error
 	DeleteByConditions(
 		ctx context.Context,
-		conditions map[string]any,
+		spec specification.Specification,
+	) error
+	Restore(
+		ctx context.Context,
+		spec specification.Specification,
+	) error
+	ForceDelete(
+		ctx context.Context,
+		spec specification.Specification,
 	) error
 	FindByConditionsWithPagination(
 		ctx context.Context,
-		pageData map[string]int,
-		conditions map[string]any,
+		page Page,
+		spec specification.Specification,
+		scopes ...func(*gorm.DB) *gorm.DB,
+	) (PageResult[D], error)
+	CreateMany(
+		ctx context.Context,
+		domains []*D,
+	) error
+	SaveMany(
+		ctx context.Context,
+		domains []*D,
+	) error
+	Upsert(
+		ctx context.Context,
+		domains []*D,
+		onConflict OnConflict,
+	) error
+	FindByConditionsWithCursor(
+		ctx context.Context,
+		page CursorPage,
+		spec specification.Specification,
+		scopes ...func(*gorm.DB) *gorm.DB,
+	) (CursorResult[D], error)
+	FindInBatches(
+		ctx context.Context,
+		spec specification.Specification,
+		batchSize int,
+		fn func(domains []*D) error,
+		scopes ...func(*gorm.DB) *gorm.DB,
+	) error
+	Iterate(
+		ctx context.Context,
+		spec specification.Specification,
+		scopes ...func(*gorm.DB) *gorm.DB,
+	) iter.Seq2[*D, error]
+	Count(
+		ctx context.Context,
+		spec specification.Specification,
+		scopes ...func(*gorm.DB) *gorm.DB,
+	) (int64, error)
+	Exists(
+		ctx context.Context,
+		spec specification.Specification,
+		scopes ...func(*gorm.DB) *gorm.DB,
+	) (bool, error)
+	GroupCount(
+		ctx context.Context,
+		column string,
+		spec specification.Specification,
+		scopes ...func(*gorm.DB) *gorm.DB,
+	) (map[string]int64, error)
+	Project(
+		ctx context.Context,
+		dest any,
+		spec specification.Specification,
+		scopes ...func(*gorm.DB) *gorm.DB,
+	) error
+	ProjectWithPagination(
+		ctx context.Context,
+		dest any,
+		page Page,
+		spec specification.Specification,
 		scopes ...func(*gorm.DB) *gorm.DB,
-	) ([]*D, int64, error)
+	) (int64, error)
+	TakeForUpdate(
+		ctx context.Context,
+		spec specification.Specification,
+		lock Lock,
+		scopes ...func(*gorm.DB) *gorm.DB,
+	) (*D, error)
+	FindForUpdate(
+		ctx context.Context,
+		spec specification.Specification,
+		lock Lock,
+		scopes ...func(*gorm.DB) *gorm.DB,
+	) ([]*D, error)
 }
 
 type Repository[A AdapterInterface[D, E], D, E any] struct {
 	db      *gorm.DB
 	logger  *logrus.Logger
 	adapter A
+	options options
+	// next is the turn of the replica of the next read.
+	next atomic.Uint64
 }
 
 func NewRepository[A AdapterInterface[D, E], D, E any](
 	db *gorm.DB,
 	logger *logrus.Logger,
 	adapter A,
+	opts ...Option,
 ) *Repository[A, D, E] {
 	return &Repository[A, D, E]{
 		db:      db,
 		logger:  logger,
 		adapter: adapter,
+		options: newOptions(opts),
 	}
 }
 
@@ -84,21 +173,67 @@ func (r *Repository[A, D, E]) DB(ctx context.Context) *gorm.DB {
 	return r.db.WithContext(ctx)
 }
 
-func (r *Repository[A, D, E]) pagination(pageData map[string]int) func(*gorm.DB) *gorm.DB {
+// inTransaction runs fn in a transaction when a write of the repository takes
+// several statements, to record its audit log or save its associations, so
+// that it is never kept partly.
+func (r *Repository[A, D, E]) inTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if r.options.recorder == nil && len(r.options.associations) == 0 {
+		return fn(ctx)
+	}
+	if _, ok := transaction.TransactionFromContext(ctx); ok {
+		return fn(ctx)
+	}
+
+	return transaction.NewTransaction(r.db).Do(ctx, fn)
+}
+
+func (r *Repository[A, D, E]) pagination(page Page) func(*gorm.DB) *gorm.DB {
 	return func(db *gorm.DB) *gorm.DB {
-		page := DefaultPage
-		if valPage, ok := pageData["page"]; ok && valPage > 0 {
-			page = valPage
-		}
+		offset := (page.Page - 1) * page.PerPage
+		return db.Offset(offset).Limit(page.PerPage)
+	}
+}
 
-		pageSize := DefaultPerPage
-		if valPageSize, ok := pageData["limit"]; ok && valPageSize > 0 {
-			pageSize = valPageSize
-		}
+// schema returns the parsed schema of the entity.
+func (r *Repository[A, D, E]) schema() (*schema.Schema, error) {
+	stmt := &gorm.Statement{DB: r.db}
+	if err := stmt.Parse(new(E)); err != nil {
+		return nil, sharedErrors.NewDomainError(sharedErrors.System, err.Error())
+	}
+
+	return stmt.Schema, nil
+}
+
+// conditions validates the columns of spec against the entity and returns
+// the scopes applying its condition and its order.
+func (r *Repository[A, D, E]) conditions(
+	spec specification.Specification,
+) (where func(*gorm.DB) *gorm.DB, order func(*gorm.DB) *gorm.DB, err error) {
+	s, err := r.schema()
+	if err != nil {
+		return nil, nil, err
+	}
 
-		offset := (page - 1) * pageSize
-		return db.Offset(offset).Limit(pageSize)
+	if err := specification.Validate(spec, s); err != nil {
+		return nil, nil, sharedErrors.NewDomainError(sharedErrors.Validation, err.Error())
+	}
+
+	where = func(db *gorm.DB) *gorm.DB {
+		if spec == nil {
+			return db
+		}
+		if expr := spec.Expression(); expr != nil {
+			return db.Where(expr)
+		}
+		return db
+	}
+	order = func(db *gorm.DB) *gorm.DB {
+		if orderBy := specification.Clause(specification.Orders(spec)); orderBy != nil {
+			return db.Clauses(orderBy)
+		}
+		return db
 	}
+	return where, order, nil
 }
 
 func (r *Repository[A, D, E]) Create(
@@ -110,21 +245,54 @@ func (r *Repository[A, D, E]) Create(
 		return err
 	}
 
-	err = r.DB(ctx).Create(entity).Error
-	if err != nil {
-		return sharedErrors.NewDomainError(sharedErrors.System, err.Error())
+	if err := r.stamp(ctx, true, entity); err != nil {
+		return err
 	}
 
-	return nil
+	versioned, isVersioned := any(entity).(sharedEntity.VersionedInterface)
+	if isVersioned && versioned.CurrentVersion() == 0 {
+		versioned.SetVersion(1)
+	}
+
+	return r.inTransaction(ctx, func(ctx context.Context) error {
+		err := r.DB(ctx).Scopes(r.omitAssociations).Create(entity).Error
+		if err != nil {
+			return sharedErrors.NewDomainError(sharedErrors.System, err.Error())
+		}
+
+		if err := r.saveAssociations(ctx, entity, true); err != nil {
+			return err
+		}
+
+		if err := r.record(ctx, audit.Create, nil, []*E{entity}); err != nil {
+			return err
+		}
+
+		if isVersioned || len(r.options.associations) > 0 {
+			return r.refresh(domain, entity)
+		}
+
+		return nil
+	})
 }
 
 func (r *Repository[A, D, E]) FindByConditions(
 	ctx context.Context,
-	conditions map[string]any,
+	spec specification.Specification,
 	scopes ...func(*gorm.DB) *gorm.DB,
 ) ([]*D, error) {
+	where, order, err := r.conditions(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	preload, err := r.preload()
+	if err != nil {
+		return nil, err
+	}
+
 	var entities []*E
-	if err := r.DB(ctx).Scopes(scopes...).Where(conditions).Find(&entities).Error; err != nil {
+	if err := r.Reader(ctx).Scopes(scopes...).Scopes(where, order, preload).Find(&entities).Error; err != nil {
 		return nil, sharedErrors.NewDomainError(sharedErrors.System, err.Error())
 	}
 
@@ -133,11 +301,21 @@ func (r *Repository[A, D, E]) FindByConditions(
 
 func (r *Repository[A, D, E]) TakeByConditions(
 	ctx context.Context,
-	conditions map[string]any,
+	spec specification.Specification,
 	scopes ...func(*gorm.DB) *gorm.DB,
 ) (*D, error) {
+	where, order, err := r.conditions(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	preload, err := r.preload()
+	if err != nil {
+		return nil, err
+	}
+
 	entity := new(E)
-	err := r.DB(ctx).Scopes(scopes...).Where(conditions).Take(entity).Error
+	err = r.Reader(ctx).Scopes(scopes...).Scopes(where, order, preload).Take(entity).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, sharedErrors.NewDomainError(sharedErrors.NotFound, err.Error())
@@ -158,55 +336,227 @@ func (r *Repository[A, D, E]) Save(
 		return err
 	}
 
-	err = r.DB(ctx).Save(entity).Error
+	if err := r.stamp(ctx, false, entity); err != nil {
+		return err
+	}
+
+	return r.inTransaction(ctx, func(ctx context.Context) error {
+		before, err := r.stored(ctx, entity)
+		if err != nil {
+			return err
+		}
+
+		if versioned, ok := any(entity).(sharedEntity.VersionedInterface); ok {
+			if err := r.saveVersioned(ctx, domain, entity, versioned); err != nil {
+				return err
+			}
+		} else if err := r.saveEntity(ctx, entity); err != nil {
+			return err
+		}
+
+		if err := r.saveAssociations(ctx, entity, false); err != nil {
+			return err
+		}
+
+		action := audit.Update
+		if before == nil {
+			action = audit.Create
+		}
+		if err := r.record(ctx, action, []*E{before}, []*E{entity}); err != nil {
+			return err
+		}
+
+		if len(r.options.associations) > 0 {
+			return r.refresh(domain, entity)
+		}
+
+		return nil
+	})
+}
+
+// saveEntity updates every column of the row of entity but its created
+// columns, or inserts entity with its created columns stamped when it has no
+// row yet. A soft deleted row is neither updated nor restored.
+func (r *Repository[A, D, E]) saveEntity(ctx context.Context, entity *E) error {
+	s, err := r.schema()
 	if err != nil {
+		return err
+	}
+
+	if pk := s.PrioritizedPrimaryField; pk != nil {
+		if id, zero := pk.ValueOf(ctx, reflect.ValueOf(entity)); !zero {
+			result := r.DB(ctx).
+				Model(entity).
+				Select("*").
+				Scopes(omitCreated[E], r.omitAssociations).
+				Updates(entity)
+			if result.Error != nil {
+				return sharedErrors.NewDomainError(sharedErrors.System, result.Error.Error())
+			}
+			if result.RowsAffected > 0 {
+				return nil
+			}
+
+			// MySQL reports no affected row when the values are unchanged, so
+			// the row is looked up before inserting entity.
+			exists, deleted, err := r.rowState(ctx, s, pk, id)
+			if err != nil {
+				return err
+			}
+			if deleted {
+				return sharedErrors.NewDomainError(sharedErrors.NotFound, fmt.Sprintf("row %v of %s is deleted", id, s.Table))
+			}
+			if exists {
+				return nil
+			}
+		}
+	}
+
+	if err := r.stamp(ctx, true, entity); err != nil {
+		return err
+	}
+	if err := r.DB(ctx).Scopes(r.omitAssociations).Create(entity).Error; err != nil {
 		return sharedErrors.NewDomainError(sharedErrors.System, err.Error())
 	}
 
 	return nil
 }
 
+// rowState returns whether the row of primary key id exists, and whether it
+// is soft deleted.
+func (r *Repository[A, D, E]) rowState(ctx context.Context, s *schema.Schema, pk *schema.Field, id any) (bool, bool, error) {
+	where := clause.Eq{Column: clause.Column{Table: clause.CurrentTable, Name: pk.DBName}, Value: id}
+
+	var count int64
+	if err := r.DB(ctx).Model(new(E)).Where(where).Count(&count).Error; err != nil {
+		return false, false, sharedErrors.NewDomainError(sharedErrors.System, err.Error())
+	}
+	if count > 0 || !isSoftDeleted(s) {
+		return count > 0, false, nil
+	}
+
+	if err := r.DB(ctx).Unscoped().Model(new(E)).Where(where).Count(&count).Error; err != nil {
+		return false, false, sharedErrors.NewDomainError(sharedErrors.System, err.Error())
+	}
+	return false, count > 0, nil
+}
+
+// requireCondition rejects a spec without condition for a write that would
+// otherwise apply to every row.
+func requireCondition(spec specification.Specification, action string) error {
+	if spec == nil || spec.Expression() == nil {
+		return sharedErrors.NewDomainError(sharedErrors.BadRequest, action+" requires a condition")
+	}
+	return nil
+}
+
 func (r *Repository[A, D, E]) DeleteByConditions(
 	ctx context.Context,
-	conditions map[string]any,
+	spec specification.Specification,
 ) error {
-	entity := new(E)
-	err := r.DB(ctx).Where(conditions).Delete(entity).Error
+	where, _, err := r.conditions(spec)
 	if err != nil {
-		return sharedErrors.NewDomainError(sharedErrors.System, err.Error())
+		return err
+	}
+	if err := requireCondition(spec, "deleting"); err != nil {
+		return err
 	}
 
-	return nil
+	s, err := r.schema()
+	if err != nil {
+		return err
+	}
+
+	return r.inTransaction(ctx, func(ctx context.Context) error {
+		var befores []*E
+		if r.options.recorder != nil {
+			if err := r.DB(ctx).Scopes(where).Find(&befores).Error; err != nil {
+				return sharedErrors.NewDomainError(sharedErrors.System, err.Error())
+			}
+		}
+
+		if isSoftDeleted(s) {
+			if err := r.softDelete(ctx, s, where); err != nil {
+				return err
+			}
+		} else if err := r.DB(ctx).Scopes(where).Delete(new(E)).Error; err != nil {
+			return sharedErrors.NewDomainError(sharedErrors.System, err.Error())
+		}
+
+		return r.record(ctx, audit.Delete, befores, nil)
+	})
 }
 
 func (r *Repository[A, D, E]) FindByConditionsWithPagination(
 	ctx context.Context,
-	pageData map[string]int,
-	conditions map[string]any,
+	page Page,
+	spec specification.Specification,
 	scopes ...func(*gorm.DB) *gorm.DB,
-) ([]*D, int64, error) {
-	cdb := r.DB(ctx)
+) (PageResult[D], error) {
+	preload, err := r.preload()
+	if err != nil {
+		return PageResult[D]{}, err
+	}
+
+	query, count, page, err := r.paginate(ctx, page, spec, scopes)
+	if err != nil {
+		return PageResult[D]{}, err
+	}
 
 	var entities []*E
-	var count int64
+	err = query.Scopes(preload).Find(&entities).Error
+	if err != nil {
+		return PageResult[D]{}, sharedErrors.NewDomainError(sharedErrors.System, err.Error())
+	}
+
+	domains, err := r.adapter.ToDomains(entities)
+	if err != nil {
+		return PageResult[D]{}, err
+	}
 
-	countBuilder := cdb.Model(&entities)
-	queryBuilder := cdb.Scopes(r.pagination(pageData))
+	return NewPageResult(domains, count, page), nil
+}
 
-	err := countBuilder.Scopes(scopes...).Where(conditions).Count(&count).Error
+// paginate counts the rows matching spec and returns the query of the rows of
+// page, ordered by pageOrders, along with page given its defaults.
+func (r *Repository[A, D, E]) paginate(
+	ctx context.Context,
+	page Page,
+	spec specification.Specification,
+	scopes []func(*gorm.DB) *gorm.DB,
+) (*gorm.DB, int64, Page, error) {
+	if err := page.Validate(); err != nil {
+		return nil, 0, Page{}, err
+	}
+	page = page.withDefaults()
+
+	where, _, err := r.conditions(spec)
 	if err != nil {
-		return []*D{}, 0, sharedErrors.NewDomainError(sharedErrors.System, err.Error())
+		return nil, 0, Page{}, err
 	}
 
-	err = queryBuilder.Scopes(scopes...).Where(conditions).Find(&entities).Error
+	s, err := r.schema()
 	if err != nil {
-		return []*D{}, 0, sharedErrors.NewDomainError(sharedErrors.System, err.Error())
+		return nil, 0, Page{}, err
 	}
 
-	domains, err := r.adapter.ToDomains(entities)
+	orders, err := r.pageOrders(s, page, spec)
 	if err != nil {
-		return []*D{}, 0, err
+		return nil, 0, Page{}, err
+	}
+
+	cdb := r.Reader(ctx)
+
+	var count int64
+	err = cdb.Model(new(E)).Scopes(scopes...).Scopes(where).Count(&count).Error
+	if err != nil {
+		return nil, 0, Page{}, sharedErrors.NewDomainError(sharedErrors.System, err.Error())
+	}
+
+	query := cdb.Model(new(E)).Scopes(r.pagination(page))
+	if orderBy := specification.Clause(orders); orderBy != nil {
+		query = query.Clauses(orderBy)
 	}
 
-	return domains, count, nil
+	return query.Scopes(scopes...).Scopes(where), count, page, nil
 }
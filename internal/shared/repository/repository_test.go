@@ -2,11 +2,13 @@ package repository
 
 import (
 	"context"
+	"regexp"
 	"testing"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/ming-0x0/hexago/internal/shared/dbmocker"
 	"github.com/ming-0x0/hexago/internal/shared/errors"
+	"github.com/ming-0x0/hexago/internal/shared/specification"
 	"github.com/ming-0x0/hexago/internal/shared/transaction"
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
@@ -18,6 +20,10 @@ type DummyEntity struct {
 	Name string
 }
 
+func (e *DummyEntity) SortableColumns() []string {
+	return []string{"name"}
+}
+
 type DummyDomain struct {
 	ID   int
 	Name string
@@ -38,12 +44,17 @@ type DummyAdapter struct {
 	ShouldFailToEntity   bool
 	ShouldFailToDomains  bool
 	ShouldFailToEntities bool
+	// ValidatesName rejects entities without a name, as a domain requiring it would.
+	ValidatesName bool
 }
 
 func (a *DummyAdapter) ToDomain(entity *DummyEntity) (*DummyDomain, error) {
 	if a.ShouldFailToDomain {
 		return nil, errors.NewDomainError(errors.Validation, "failed to convert to domain")
 	}
+	if a.ValidatesName && entity.Name == "" {
+		return nil, errors.NewDomainError(errors.Validation, "name is required")
+	}
 	return &DummyDomain{ID: entity.ID, Name: entity.Name}, nil
 }
 
@@ -86,7 +97,7 @@ func (a *DummyAdapter) ToEntities(domains []*DummyDomain) ([]*DummyEntity, error
 	return entities, nil
 }
 
-func setupTest(t *testing.T, adapterConfig DummyAdapter) (*Repository[*DummyAdapter, DummyDomain, DummyEntity], *dbmocker.MockedRepository, *gorm.DB, sqlmock.Sqlmock, *DummyAdapter, *logrus.Logger) {
+func setupTest(t *testing.T, adapterConfig DummyAdapter, opts ...Option) (*Repository[*DummyAdapter, DummyDomain, DummyEntity], *dbmocker.MockedRepository, *gorm.DB, sqlmock.Sqlmock, *DummyAdapter, *logrus.Logger) {
 	logger := logrus.New()
 	mockedDB, err := dbmocker.NewMockedDB()
 	if err != nil {
@@ -95,7 +106,7 @@ func setupTest(t *testing.T, adapterConfig DummyAdapter) (*Repository[*DummyAdap
 	_, gormDB, sqlMock := mockedDB.DB, mockedDB.GormDB, mockedDB.SqlMock
 
 	adapter := &adapterConfig
-	repo := NewRepository(gormDB, logger, adapter)
+	repo := NewRepository(gormDB, logger, adapter, opts...)
 	return repo, mockedDB, gormDB, sqlMock, adapter, logger
 }
 
@@ -168,7 +179,7 @@ func TestRepository_FindByConditions(t *testing.T) {
 	t.Parallel()
 
 	type args struct {
-		conditions map[string]any
+		spec specification.Specification
 	}
 
 	tests := []struct {
@@ -181,7 +192,7 @@ func TestRepository_FindByConditions(t *testing.T) {
 	}{
 		{
 			name:          "Success",
-			args:          args{conditions: map[string]any{"name": "Test"}},
+			args:          args{spec: specification.Eq("name", "Test")},
 			adapterConfig: DummyAdapter{},
 			setupMock: func(mock sqlmock.Sqlmock) {
 				rows := sqlmock.NewRows([]string{"id", "name"}).
@@ -192,9 +203,57 @@ func TestRepository_FindByConditions(t *testing.T) {
 			assertion: assert.NoError,
 			expected:  []*DummyDomain{{ID: 1, Name: "Test1"}, {ID: 2, Name: "Test2"}},
 		},
+		{
+			name: "Success_RangeOrOrdered",
+			args: args{spec: specification.OrderBy(
+				specification.Or(
+					specification.Between("id", 1, 10),
+					specification.Like("name", "Test%"),
+				),
+				specification.Desc("id"),
+			)},
+			adapterConfig: DummyAdapter{},
+			setupMock: func(mock sqlmock.Sqlmock) {
+				rows := sqlmock.NewRows([]string{"id", "name"}).
+					AddRow(2, "Test2").
+					AddRow(1, "Test1")
+				mock.ExpectQuery(regexp.QuoteMeta(
+					"SELECT * FROM `dummy_entities` WHERE ((`dummy_entities`.`id` BETWEEN ? AND ?) OR `dummy_entities`.`name` LIKE ?) ORDER BY `dummy_entities`.`id` DESC",
+				)).WithArgs(1, 10, "Test%").WillReturnRows(rows)
+			},
+			assertion: assert.NoError,
+			expected:  []*DummyDomain{{ID: 2, Name: "Test2"}, {ID: 1, Name: "Test1"}},
+		},
+		{
+			name:          "Success_NilSpecification",
+			args:          args{spec: nil},
+			adapterConfig: DummyAdapter{},
+			setupMock: func(mock sqlmock.Sqlmock) {
+				rows := sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "Test1")
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `dummy_entities`")).WillReturnRows(rows)
+			},
+			assertion: assert.NoError,
+			expected:  []*DummyDomain{{ID: 1, Name: "Test1"}},
+		},
+		{
+			name:          "Failure_UnknownColumn",
+			args:          args{spec: specification.Eq("nmae", "Test")},
+			adapterConfig: DummyAdapter{},
+			setupMock:     func(mock sqlmock.Sqlmock) {},
+			assertion:     assert.Error,
+			expected:      nil,
+		},
+		{
+			name:          "Failure_UnknownOrderColumn",
+			args:          args{spec: specification.OrderBy(nil, specification.Asc("created"))},
+			adapterConfig: DummyAdapter{},
+			setupMock:     func(mock sqlmock.Sqlmock) {},
+			assertion:     assert.Error,
+			expected:      nil,
+		},
 		{
 			name:          "Failure_DBError",
-			args:          args{conditions: map[string]any{"name": "Test"}},
+			args:          args{spec: specification.Eq("name", "Test")},
 			adapterConfig: DummyAdapter{},
 			setupMock: func(mock sqlmock.Sqlmock) {
 				mock.ExpectQuery("SELECT (.+) FROM `dummy_entities`").WillReturnError(gorm.ErrInvalidField)
@@ -204,7 +263,7 @@ func TestRepository_FindByConditions(t *testing.T) {
 		},
 		{
 			name:          "Failure_AdapterToDomainsError",
-			args:          args{conditions: map[string]any{"name": "Test"}},
+			args:          args{spec: specification.Eq("name", "Test")},
 			adapterConfig: DummyAdapter{ShouldFailToDomains: true},
 			setupMock: func(mock sqlmock.Sqlmock) {
 				rows := sqlmock.NewRows([]string{"id", "name"}).
@@ -225,7 +284,7 @@ func TestRepository_FindByConditions(t *testing.T) {
 			defer teardownTest(mockedDB)
 
 			tc.setupMock(sqlMock)
-			data, err := repo.FindByConditions(context.Background(), tc.args.conditions)
+			data, err := repo.FindByConditions(context.Background(), tc.args.spec)
 			tc.assertion(t, err)
 			if err == nil {
 				assert.Equal(t, tc.expected, data)
@@ -238,7 +297,7 @@ func TestRepository_TakeByConditions(t *testing.T) {
 	t.Parallel()
 
 	type args struct {
-		conditions map[string]any
+		spec specification.Specification
 	}
 
 	tests := []struct {
@@ -251,7 +310,7 @@ func TestRepository_TakeByConditions(t *testing.T) {
 	}{
 		{
 			name:          "Success",
-			args:          args{conditions: map[string]any{"id": 1}},
+			args:          args{spec: specification.Eq("id", 1)},
 			adapterConfig: DummyAdapter{},
 			setupMock: func(mock sqlmock.Sqlmock) {
 				rows := sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "Test")
@@ -262,7 +321,7 @@ func TestRepository_TakeByConditions(t *testing.T) {
 		},
 		{
 			name:          "Failure_NotFound",
-			args:          args{conditions: map[string]any{"id": 1}},
+			args:          args{spec: specification.Eq("id", 1)},
 			adapterConfig: DummyAdapter{},
 			setupMock: func(mock sqlmock.Sqlmock) {
 				mock.ExpectQuery("SELECT (.+) FROM `dummy_entities`").WillReturnError(gorm.ErrRecordNotFound)
@@ -272,7 +331,7 @@ func TestRepository_TakeByConditions(t *testing.T) {
 		},
 		{
 			name:          "Failure_AdapterToDomainError",
-			args:          args{conditions: map[string]any{"id": 1}},
+			args:          args{spec: specification.Eq("id", 1)},
 			adapterConfig: DummyAdapter{ShouldFailToDomain: true},
 			setupMock: func(mock sqlmock.Sqlmock) {
 				rows := sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "Test")
@@ -283,7 +342,7 @@ func TestRepository_TakeByConditions(t *testing.T) {
 		},
 		{
 			name:          "Failure_DBError",
-			args:          args{conditions: map[string]any{"id": 1}},
+			args:          args{spec: specification.Eq("id", 1)},
 			adapterConfig: DummyAdapter{},
 			setupMock: func(mock sqlmock.Sqlmock) {
 				mock.ExpectQuery("SELECT (.+) FROM `dummy_entities`").WillReturnError(gorm.ErrInvalidField)
@@ -301,7 +360,7 @@ func TestRepository_TakeByConditions(t *testing.T) {
 			defer teardownTest(mockedDB)
 
 			tc.setupMock(sqlMock)
-			data, err := repo.TakeByConditions(context.Background(), tc.args.conditions)
+			data, err := repo.TakeByConditions(context.Background(), tc.args.spec)
 			tc.assertion(t, err)
 			if err == nil {
 				assert.Equal(t, tc.expected, data)
@@ -373,7 +432,7 @@ func TestRepository_DeleteByConditions(t *testing.T) {
 	t.Parallel()
 
 	type args struct {
-		conditions map[string]any
+		spec specification.Specification
 	}
 
 	tests := []struct {
@@ -384,7 +443,7 @@ func TestRepository_DeleteByConditions(t *testing.T) {
 	}{
 		{
 			name: "Success",
-			args: args{conditions: map[string]any{"id": 1}},
+			args: args{spec: specification.Eq("id", 1)},
 			setupMock: func(mock sqlmock.Sqlmock) {
 				mock.ExpectBegin()
 				mock.ExpectExec("DELETE FROM `dummy_entities`").WillReturnResult(sqlmock.NewResult(0, 1))
@@ -394,7 +453,7 @@ func TestRepository_DeleteByConditions(t *testing.T) {
 		},
 		{
 			name: "Failure_DBError",
-			args: args{conditions: map[string]any{"id": 1}},
+			args: args{spec: specification.Eq("id", 1)},
 			setupMock: func(mock sqlmock.Sqlmock) {
 				mock.ExpectBegin()
 				mock.ExpectExec("DELETE FROM `dummy_entities`").WillReturnError(gorm.ErrInvalidField)
@@ -402,6 +461,18 @@ func TestRepository_DeleteByConditions(t *testing.T) {
 			},
 			assertion: assert.Error,
 		},
+		{
+			name:      "Failure_UnknownColumn",
+			args:      args{spec: specification.In("identifier", 1, 2)},
+			setupMock: func(mock sqlmock.Sqlmock) {},
+			assertion: assert.Error,
+		},
+		{
+			name:      "Failure_NoCondition",
+			args:      args{spec: nil},
+			setupMock: func(mock sqlmock.Sqlmock) {},
+			assertion: assert.Error,
+		},
 	}
 
 	for _, tt := range tests {
@@ -412,7 +483,7 @@ func TestRepository_DeleteByConditions(t *testing.T) {
 			defer teardownTest(mockedDB)
 
 			tc.setupMock(sqlMock)
-			err := repo.DeleteByConditions(context.Background(), tc.args.conditions)
+			err := repo.DeleteByConditions(context.Background(), tc.args.spec)
 			tc.assertion(t, err)
 		})
 	}
@@ -422,8 +493,8 @@ func TestRepository_FindByConditionsWithPagination(t *testing.T) {
 	t.Parallel()
 
 	type args struct {
-		pagination map[string]int
-		conditions map[string]any
+		page Page
+		spec specification.Specification
 	}
 
 	tests := []struct {
@@ -432,14 +503,13 @@ func TestRepository_FindByConditionsWithPagination(t *testing.T) {
 		adapterConfig DummyAdapter
 		setupMock     func(sqlmock.Sqlmock)
 		assertion     assert.ErrorAssertionFunc
-		expected      []*DummyDomain
-		count         int64
+		expected      PageResult[DummyDomain]
 	}{
 		{
 			name: "Success_DefaultPagination",
 			args: args{
-				pagination: map[string]int{}, // Empty map to test default pagination values
-				conditions: map[string]any{"name": "Test"},
+				page: Page{}, // Zero page to test default pagination values
+				spec: specification.Eq("name", "Test"),
 			},
 			adapterConfig: DummyAdapter{},
 			setupMock: func(mock sqlmock.Sqlmock) {
@@ -448,20 +518,26 @@ func TestRepository_FindByConditionsWithPagination(t *testing.T) {
 					AddRow(1, "Test1").
 					AddRow(2, "Test2")
 				mock.ExpectQuery("SELECT count\\(\\*\\) FROM `dummy_entities`").WillReturnRows(countRows)
-				mock.ExpectQuery("SELECT (.+) FROM `dummy_entities`").WillReturnRows(rows)
+				mock.ExpectQuery("SELECT (.+) FROM `dummy_entities`").WithArgs("Test", DefaultPerPage).WillReturnRows(rows)
 			},
 			assertion: assert.NoError,
-			expected: []*DummyDomain{
-				{ID: 1, Name: "Test1"},
-				{ID: 2, Name: "Test2"},
+			expected: PageResult[DummyDomain]{
+				Items: []*DummyDomain{
+					{ID: 1, Name: "Test1"},
+					{ID: 2, Name: "Test2"},
+				},
+				Total:      5,
+				Page:       DefaultPage,
+				PerPage:    DefaultPerPage,
+				TotalPages: 1,
+				HasNext:    false,
 			},
-			count: 5,
 		},
 		{
 			name: "Success_CustomPagination",
 			args: args{
-				pagination: map[string]int{"page": 2, "limit": 3},
-				conditions: map[string]any{"name": "Test"},
+				page: Page{Page: 2, PerPage: 3},
+				spec: specification.Eq("name", "Test"),
 			},
 			adapterConfig: DummyAdapter{},
 			setupMock: func(mock sqlmock.Sqlmock) {
@@ -471,21 +547,27 @@ func TestRepository_FindByConditionsWithPagination(t *testing.T) {
 					AddRow(5, "Test5").
 					AddRow(6, "Test6")
 				mock.ExpectQuery("SELECT count\\(\\*\\) FROM `dummy_entities`").WillReturnRows(countRows)
-				mock.ExpectQuery("SELECT (.+) FROM `dummy_entities`").WillReturnRows(rows)
+				mock.ExpectQuery("SELECT (.+) FROM `dummy_entities`").WithArgs("Test", 3, 3).WillReturnRows(rows)
 			},
 			assertion: assert.NoError,
-			expected: []*DummyDomain{
-				{ID: 4, Name: "Test4"},
-				{ID: 5, Name: "Test5"},
-				{ID: 6, Name: "Test6"},
+			expected: PageResult[DummyDomain]{
+				Items: []*DummyDomain{
+					{ID: 4, Name: "Test4"},
+					{ID: 5, Name: "Test5"},
+					{ID: 6, Name: "Test6"},
+				},
+				Total:      10,
+				Page:       2,
+				PerPage:    3,
+				TotalPages: 4,
+				HasNext:    true,
 			},
-			count: 10,
 		},
 		{
 			name: "Success_ZeroPageAndLimit",
 			args: args{
-				pagination: map[string]int{"page": 0, "limit": 0}, // Test handling of zero or invalid values
-				conditions: map[string]any{"name": "Test"},
+				page: Page{Page: 0, PerPage: 0}, // Zero values fall back to the defaults
+				spec: specification.Eq("name", "Test"),
 			},
 			adapterConfig: DummyAdapter{},
 			setupMock: func(mock sqlmock.Sqlmock) {
@@ -494,34 +576,80 @@ func TestRepository_FindByConditionsWithPagination(t *testing.T) {
 					AddRow(1, "Test1").
 					AddRow(2, "Test2")
 				mock.ExpectQuery("SELECT count\\(\\*\\) FROM `dummy_entities`").WillReturnRows(countRows)
-				mock.ExpectQuery("SELECT (.+) FROM `dummy_entities`").WillReturnRows(rows)
+				mock.ExpectQuery("SELECT (.+) FROM `dummy_entities`").WithArgs("Test", DefaultPerPage).WillReturnRows(rows)
+			},
+			assertion: assert.NoError,
+			expected: PageResult[DummyDomain]{
+				Items: []*DummyDomain{
+					{ID: 1, Name: "Test1"},
+					{ID: 2, Name: "Test2"},
+				},
+				Total:      5,
+				Page:       DefaultPage,
+				PerPage:    DefaultPerPage,
+				TotalPages: 1,
+				HasNext:    false,
+			},
+		},
+		{
+			name: "Success_Empty",
+			args: args{
+				page: Page{Page: 3, PerPage: 10},
+				spec: specification.Eq("name", "Test"),
+			},
+			adapterConfig: DummyAdapter{},
+			setupMock: func(mock sqlmock.Sqlmock) {
+				countRows := sqlmock.NewRows([]string{"count"}).AddRow(0)
+				mock.ExpectQuery("SELECT count\\(\\*\\) FROM `dummy_entities`").WillReturnRows(countRows)
+				mock.ExpectQuery("SELECT (.+) FROM `dummy_entities`").WillReturnRows(sqlmock.NewRows([]string{"id", "name"}))
 			},
 			assertion: assert.NoError,
-			expected: []*DummyDomain{
-				{ID: 1, Name: "Test1"},
-				{ID: 2, Name: "Test2"},
+			expected: PageResult[DummyDomain]{
+				Items:      []*DummyDomain{},
+				Total:      0,
+				Page:       3,
+				PerPage:    10,
+				TotalPages: 0,
+				HasNext:    false,
+			},
+		},
+		{
+			name: "Failure_PerPageTooLarge",
+			args: args{
+				page: Page{Page: 1, PerPage: MaxPerPage + 1},
+				spec: specification.Eq("name", "Test"),
+			},
+			adapterConfig: DummyAdapter{},
+			setupMock:     func(mock sqlmock.Sqlmock) {},
+			assertion:     assert.Error,
+		},
+		{
+			name: "Failure_NegativePage",
+			args: args{
+				page: Page{Page: -1, PerPage: 2},
+				spec: specification.Eq("name", "Test"),
 			},
-			count: 5,
+			adapterConfig: DummyAdapter{},
+			setupMock:     func(mock sqlmock.Sqlmock) {},
+			assertion:     assert.Error,
 		},
 		{
 			name: "Failure_CountError",
 			args: args{
-				pagination: map[string]int{"page": 1, "limit": 2},
-				conditions: map[string]any{"name": "Test"},
+				page: Page{Page: 1, PerPage: 2},
+				spec: specification.Eq("name", "Test"),
 			},
 			adapterConfig: DummyAdapter{},
 			setupMock: func(mock sqlmock.Sqlmock) {
 				mock.ExpectQuery("SELECT count\\(\\*\\) FROM `dummy_entities`").WillReturnError(gorm.ErrInvalidField)
 			},
 			assertion: assert.Error,
-			expected:  nil,
-			count:     0,
 		},
 		{
 			name: "Failure_QueryError",
 			args: args{
-				pagination: map[string]int{"page": 1, "limit": 2},
-				conditions: map[string]any{"name": "Test"},
+				page: Page{Page: 1, PerPage: 2},
+				spec: specification.Eq("name", "Test"),
 			},
 			adapterConfig: DummyAdapter{},
 			setupMock: func(mock sqlmock.Sqlmock) {
@@ -530,14 +658,12 @@ func TestRepository_FindByConditionsWithPagination(t *testing.T) {
 				mock.ExpectQuery("SELECT (.+) FROM `dummy_entities`").WillReturnError(gorm.ErrInvalidField)
 			},
 			assertion: assert.Error,
-			expected:  nil,
-			count:     0,
 		},
 		{
 			name: "Failure_AdapterToDomainsError",
 			args: args{
-				pagination: map[string]int{"page": 1, "limit": 2},
-				conditions: map[string]any{"name": "Test"},
+				page: Page{Page: 1, PerPage: 2},
+				spec: specification.Eq("name", "Test"),
 			},
 			adapterConfig: DummyAdapter{ShouldFailToDomains: true},
 			setupMock: func(mock sqlmock.Sqlmock) {
@@ -549,8 +675,6 @@ func TestRepository_FindByConditionsWithPagination(t *testing.T) {
 				mock.ExpectQuery("SELECT (.+) FROM `dummy_entities`").WillReturnRows(rows)
 			},
 			assertion: assert.Error,
-			expected:  nil,
-			count:     0,
 		},
 	}
 
@@ -562,11 +686,10 @@ func TestRepository_FindByConditionsWithPagination(t *testing.T) {
 			defer teardownTest(mockedDB)
 
 			tc.setupMock(sqlMock)
-			data, count, err := repo.FindByConditionsWithPagination(context.Background(), tc.args.pagination, tc.args.conditions)
+			result, err := repo.FindByConditionsWithPagination(context.Background(), tc.args.page, tc.args.spec)
 			tc.assertion(t, err)
 			if err == nil {
-				assert.Equal(t, tc.expected, data)
-				assert.Equal(t, tc.count, count)
+				assert.Equal(t, tc.expected, result)
 			}
 		})
 	}
@@ -0,0 +1,125 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	sharedEntity "github.com/ming-0x0/hexago/internal/shared/entity"
+	sharedErrors "github.com/ming-0x0/hexago/internal/shared/errors"
+	"github.com/ming-0x0/hexago/internal/shared/specification"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// WithDeleted is a scope including soft deleted rows in a query.
+func WithDeleted(db *gorm.DB) *gorm.DB {
+	return db.Unscoped()
+}
+
+// OnlyDeleted is a scope restricting a query to soft deleted rows.
+func OnlyDeleted(db *gorm.DB) *gorm.DB {
+	return db.Unscoped().Where(clause.Neq{
+		Column: clause.Column{Table: clause.CurrentTable, Name: sharedEntity.DeletedAtColumn},
+		Value:  nil,
+	})
+}
+
+func isSoftDeleted(s *schema.Schema) bool {
+	_, ok := s.FieldsByDBName[sharedEntity.DeletedAtColumn]
+	return ok
+}
+
+// softDelete marks the rows of the table of s selected by where as deleted by
+// the actor of ctx.
+func (r *Repository[A, D, E]) softDelete(
+	ctx context.Context,
+	s *schema.Schema,
+	where func(*gorm.DB) *gorm.DB,
+) error {
+	values := map[string]any{
+		sharedEntity.DeletedAtColumn: r.db.NowFunc(),
+	}
+	if _, ok := s.FieldsByDBName[sharedEntity.DeletedByColumn]; ok {
+		id, err := actorID(ctx)
+		if err != nil {
+			return err
+		}
+		values[sharedEntity.DeletedByColumn] = id
+	}
+
+	// UpdateColumns keeps the update time, as gorm does when soft deleting.
+	model := reflect.New(s.ModelType).Interface()
+	err := r.DB(ctx).Model(model).Scopes(where).UpdateColumns(values).Error
+	if err != nil {
+		return sharedErrors.NewDomainError(sharedErrors.System, err.Error())
+	}
+
+	return nil
+}
+
+// Restore undeletes the soft deleted rows matching spec.
+func (r *Repository[A, D, E]) Restore(
+	ctx context.Context,
+	spec specification.Specification,
+) error {
+	where, _, err := r.conditions(spec)
+	if err != nil {
+		return err
+	}
+	if err := requireCondition(spec, "restoring"); err != nil {
+		return err
+	}
+
+	s, err := r.schema()
+	if err != nil {
+		return err
+	}
+	if !isSoftDeleted(s) {
+		return sharedErrors.NewDomainError(sharedErrors.BadRequest, fmt.Sprintf("table %s has no soft delete column", s.Table))
+	}
+
+	values := map[string]any{
+		sharedEntity.DeletedAtColumn: nil,
+	}
+	if _, ok := s.FieldsByDBName[sharedEntity.DeletedByColumn]; ok {
+		values[sharedEntity.DeletedByColumn] = nil
+	}
+	if isAuditable[E]() {
+		id, err := actorID(ctx)
+		if err != nil {
+			return err
+		}
+		values[sharedEntity.UpdatedByColumn] = id
+		values[sharedEntity.UpdatedAtColumn] = r.db.NowFunc()
+	}
+
+	err = r.DB(ctx).Model(new(E)).Scopes(OnlyDeleted, where).UpdateColumns(values).Error
+	if err != nil {
+		return sharedErrors.NewDomainError(sharedErrors.System, err.Error())
+	}
+
+	return nil
+}
+
+// ForceDelete permanently deletes the rows matching spec, soft deleted or not.
+func (r *Repository[A, D, E]) ForceDelete(
+	ctx context.Context,
+	spec specification.Specification,
+) error {
+	where, _, err := r.conditions(spec)
+	if err != nil {
+		return err
+	}
+	if err := requireCondition(spec, "force deleting"); err != nil {
+		return err
+	}
+
+	err = r.DB(ctx).Unscoped().Scopes(where).Delete(new(E)).Error
+	if err != nil {
+		return sharedErrors.NewDomainError(sharedErrors.System, err.Error())
+	}
+
+	return nil
+}
@@ -0,0 +1,276 @@
+package repository
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/ming-0x0/hexago/internal/shared/actor"
+	"github.com/ming-0x0/hexago/internal/shared/dbmocker"
+	sharedEntity "github.com/ming-0x0/hexago/internal/shared/entity"
+	sharedErrors "github.com/ming-0x0/hexago/internal/shared/errors"
+	"github.com/ming-0x0/hexago/internal/shared/specification"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+type SoftDummyEntity struct {
+	ID   int
+	Name string
+	sharedEntity.BaseEntityWithDeleted
+}
+
+type SoftDummyAdapter struct{}
+
+func (a *SoftDummyAdapter) ToDomain(entity *SoftDummyEntity) (*DummyDomain, error) {
+	return &DummyDomain{ID: entity.ID, Name: entity.Name}, nil
+}
+
+func (a *SoftDummyAdapter) ToEntity(domain *DummyDomain) (*SoftDummyEntity, error) {
+	return &SoftDummyEntity{ID: domain.ID, Name: domain.Name}, nil
+}
+
+func (a *SoftDummyAdapter) ToDomains(entities []*SoftDummyEntity) ([]*DummyDomain, error) {
+	domains := make([]*DummyDomain, 0, len(entities))
+	for _, e := range entities {
+		d, _ := a.ToDomain(e)
+		domains = append(domains, d)
+	}
+	return domains, nil
+}
+
+func (a *SoftDummyAdapter) ToEntities(domains []*DummyDomain) ([]*SoftDummyEntity, error) {
+	entities := make([]*SoftDummyEntity, 0, len(domains))
+	for _, d := range domains {
+		e, _ := a.ToEntity(d)
+		entities = append(entities, e)
+	}
+	return entities, nil
+}
+
+func setupSoftDeleteTest(t *testing.T) (*Repository[*SoftDummyAdapter, DummyDomain, SoftDummyEntity], *dbmocker.MockedRepository, sqlmock.Sqlmock) {
+	mockedDB, err := dbmocker.NewMockedDB()
+	if err != nil {
+		t.Fatalf("error when creating mock DB: %v", err)
+	}
+
+	repo := NewRepository(mockedDB.GormDB, mockedDB.Logger, &SoftDummyAdapter{})
+	return repo, mockedDB, mockedDB.SqlMock
+}
+
+func TestRepository_DeleteByConditions_SoftDelete(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		ctx       context.Context
+		spec      specification.Specification
+		setupMock func(sqlmock.Sqlmock)
+		assertion assert.ErrorAssertionFunc
+	}{
+		{
+			name: "Success_WithActor",
+			ctx:  actor.WithID(context.Background(), "01ARZ3NDEKTSV4RRFFQ69G5FAV"),
+			spec: specification.Eq("id", 1),
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(regexp.QuoteMeta(
+					"UPDATE `soft_dummy_entities` SET `deleted_at`=?,`deleted_by`=? WHERE `soft_dummy_entities`.`id` = ? AND `soft_dummy_entities`.`deleted_at` IS NULL",
+				)).WithArgs(sqlmock.AnyArg(), "01ARZ3NDEKTSV4RRFFQ69G5FAV", 1).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+			assertion: assert.NoError,
+		},
+		{
+			name:      "Failure_NoActor",
+			ctx:       context.Background(),
+			spec:      specification.Eq("id", 1),
+			setupMock: func(mock sqlmock.Sqlmock) {},
+			assertion: assert.Error,
+		},
+		{
+			name:      "Failure_NoCondition",
+			ctx:       context.Background(),
+			spec:      nil,
+			setupMock: func(mock sqlmock.Sqlmock) {},
+			assertion: assert.Error,
+		},
+		{
+			name: "Failure_DBError",
+			ctx:  actor.WithID(context.Background(), "01ARZ3NDEKTSV4RRFFQ69G5FAV"),
+			spec: specification.Eq("id", 1),
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec("UPDATE `soft_dummy_entities`").WillReturnError(gorm.ErrInvalidField)
+				mock.ExpectRollback()
+			},
+			assertion: assert.Error,
+		},
+	}
+
+	for _, tt := range tests {
+		tc := tt
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			repo, mockedDB, sqlMock := setupSoftDeleteTest(t)
+			defer teardownTest(mockedDB)
+
+			tc.setupMock(sqlMock)
+			err := repo.DeleteByConditions(tc.ctx, tc.spec)
+			tc.assertion(t, err)
+			assert.NoError(t, sqlMock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestRepository_Restore(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Success", func(t *testing.T) {
+		t.Parallel()
+		repo, mockedDB, sqlMock := setupSoftDeleteTest(t)
+		defer teardownTest(mockedDB)
+
+		sqlMock.ExpectBegin()
+		sqlMock.ExpectExec(regexp.QuoteMeta(
+			"UPDATE `soft_dummy_entities` SET `deleted_at`=?,`deleted_by`=?,`updated_at`=?,`updated_by`=? WHERE `soft_dummy_entities`.`deleted_at` IS NOT NULL AND `soft_dummy_entities`.`id` = ?",
+		)).WithArgs(nil, nil, sqlmock.AnyArg(), "01ARZ3NDEKTSV4RRFFQ69G5FAV", 1).WillReturnResult(sqlmock.NewResult(0, 1))
+		sqlMock.ExpectCommit()
+
+		ctx := actor.WithID(context.Background(), "01ARZ3NDEKTSV4RRFFQ69G5FAV")
+		err := repo.Restore(ctx, specification.Eq("id", 1))
+		assert.NoError(t, err)
+		assert.NoError(t, sqlMock.ExpectationsWereMet())
+	})
+
+	t.Run("Failure_NoActor", func(t *testing.T) {
+		t.Parallel()
+		repo, mockedDB, sqlMock := setupSoftDeleteTest(t)
+		defer teardownTest(mockedDB)
+
+		err := repo.Restore(context.Background(), specification.Eq("id", 1))
+		assert.Error(t, err)
+		assert.NoError(t, sqlMock.ExpectationsWereMet())
+	})
+
+	t.Run("Failure_NoCondition", func(t *testing.T) {
+		t.Parallel()
+		repo, mockedDB, sqlMock := setupSoftDeleteTest(t)
+		defer teardownTest(mockedDB)
+
+		ctx := actor.WithID(context.Background(), "01ARZ3NDEKTSV4RRFFQ69G5FAV")
+		err := repo.Restore(ctx, nil)
+		var domainErr *sharedErrors.DomainError
+		assert.ErrorAs(t, err, &domainErr)
+		assert.Equal(t, sharedErrors.BadRequest, domainErr.ErrorCode())
+		assert.NoError(t, sqlMock.ExpectationsWereMet())
+	})
+
+	t.Run("Failure_NotSoftDeleted", func(t *testing.T) {
+		t.Parallel()
+		repo, mockedDB, _, sqlMock, _, _ := setupTest(t, DummyAdapter{})
+		defer teardownTest(mockedDB)
+
+		err := repo.Restore(context.Background(), specification.Eq("id", 1))
+		assert.Error(t, err)
+		assert.NoError(t, sqlMock.ExpectationsWereMet())
+	})
+
+	t.Run("Failure_UnknownColumn", func(t *testing.T) {
+		t.Parallel()
+		repo, mockedDB, sqlMock := setupSoftDeleteTest(t)
+		defer teardownTest(mockedDB)
+
+		err := repo.Restore(context.Background(), specification.Eq("deleted", 1))
+		assert.Error(t, err)
+		assert.NoError(t, sqlMock.ExpectationsWereMet())
+	})
+}
+
+func TestRepository_ForceDelete(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Success", func(t *testing.T) {
+		t.Parallel()
+		repo, mockedDB, sqlMock := setupSoftDeleteTest(t)
+		defer teardownTest(mockedDB)
+
+		sqlMock.ExpectBegin()
+		sqlMock.ExpectExec(regexp.QuoteMeta(
+			"DELETE FROM `soft_dummy_entities` WHERE `soft_dummy_entities`.`id` = ?",
+		)).WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+		sqlMock.ExpectCommit()
+
+		err := repo.ForceDelete(context.Background(), specification.Eq("id", 1))
+		assert.NoError(t, err)
+		assert.NoError(t, sqlMock.ExpectationsWereMet())
+	})
+
+	t.Run("Failure_NoCondition", func(t *testing.T) {
+		t.Parallel()
+		repo, mockedDB, sqlMock := setupSoftDeleteTest(t)
+		defer teardownTest(mockedDB)
+
+		err := repo.ForceDelete(context.Background(), nil)
+		var domainErr *sharedErrors.DomainError
+		assert.ErrorAs(t, err, &domainErr)
+		assert.Equal(t, sharedErrors.BadRequest, domainErr.ErrorCode())
+		assert.NoError(t, sqlMock.ExpectationsWereMet())
+	})
+
+	t.Run("Failure_DBError", func(t *testing.T) {
+		t.Parallel()
+		repo, mockedDB, sqlMock := setupSoftDeleteTest(t)
+		defer teardownTest(mockedDB)
+
+		sqlMock.ExpectBegin()
+		sqlMock.ExpectExec("DELETE FROM `soft_dummy_entities`").WillReturnError(gorm.ErrInvalidField)
+		sqlMock.ExpectRollback()
+
+		err := repo.ForceDelete(context.Background(), specification.Eq("id", 1))
+		assert.Error(t, err)
+	})
+}
+
+func TestRepository_FindByConditions_DeletedScopes(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		scopes   []func(*gorm.DB) *gorm.DB
+		expected string
+	}{
+		{
+			name:     "Default",
+			scopes:   nil,
+			expected: "SELECT * FROM `soft_dummy_entities` WHERE `soft_dummy_entities`.`name` = ? AND `soft_dummy_entities`.`deleted_at` IS NULL",
+		},
+		{
+			name:     "WithDeleted",
+			scopes:   []func(*gorm.DB) *gorm.DB{WithDeleted},
+			expected: "SELECT * FROM `soft_dummy_entities` WHERE `soft_dummy_entities`.`name` = ?",
+		},
+		{
+			name:     "OnlyDeleted",
+			scopes:   []func(*gorm.DB) *gorm.DB{OnlyDeleted},
+			expected: "SELECT * FROM `soft_dummy_entities` WHERE `soft_dummy_entities`.`deleted_at` IS NOT NULL AND `soft_dummy_entities`.`name` = ?",
+		},
+	}
+
+	for _, tt := range tests {
+		tc := tt
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			repo, mockedDB, sqlMock := setupSoftDeleteTest(t)
+			defer teardownTest(mockedDB)
+
+			rows := sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "Test")
+			sqlMock.ExpectQuery(regexp.QuoteMeta(tc.expected)).WithArgs("Test").WillReturnRows(rows)
+
+			data, err := repo.FindByConditions(context.Background(), specification.Eq("name", "Test"), tc.scopes...)
+			assert.NoError(t, err)
+			assert.Equal(t, []*DummyDomain{{ID: 1, Name: "Test"}}, data)
+		})
+	}
+}
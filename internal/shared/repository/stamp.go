@@ -0,0 +1,130 @@
+package repository
+
+import (
+	"context"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/ming-0x0/hexago/internal/shared/actor"
+	sharedEntity "github.com/ming-0x0/hexago/internal/shared/entity"
+	sharedErrors "github.com/ming-0x0/hexago/internal/shared/errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// createdColumns are kept when an auditable row is updated.
+var createdColumns = []string{sharedEntity.CreatedByColumn, sharedEntity.CreatedAtColumn}
+
+func isAuditable[E any]() bool {
+	_, ok := any(new(E)).(sharedEntity.AuditableInterface)
+	return ok
+}
+
+// omitCreated is a scope keeping the created columns of auditable entities when they are updated.
+func omitCreated[E any](db *gorm.DB) *gorm.DB {
+	if !isAuditable[E]() {
+		return db
+	}
+	return db.Omit(createdColumns...)
+}
+
+// actorID returns the acting user of ctx, who is required to write an entity with audit columns.
+func actorID(ctx context.Context) (string, error) {
+	id, ok := actor.IDFromContext(ctx)
+	if !ok {
+		return "", sharedErrors.NewDomainError(sharedErrors.NotAuthorized, "no actor in context to write audit columns")
+	}
+
+	return id, nil
+}
+
+// stamp sets the audit columns of auditable entities to the actor of ctx and
+// the current time. The created columns are only set when created is true.
+func (r *Repository[A, D, E]) stamp(ctx context.Context, created bool, entities ...*E) error {
+	if !isAuditable[E]() {
+		return nil
+	}
+
+	id, err := actorID(ctx)
+	if err != nil {
+		return err
+	}
+
+	now := r.db.NowFunc()
+	for _, entity := range entities {
+		auditable := any(entity).(sharedEntity.AuditableInterface)
+		if created {
+			auditable.SetCreated(id, now)
+		}
+		auditable.SetUpdated(id, now)
+	}
+
+	return nil
+}
+
+// stampChildren sets the audit columns of the auditable children of an
+// association, which are upserted: their created columns are only written
+// when they are inserted.
+func stampChildren(ctx context.Context, now time.Time, children reflect.Value) error {
+	id, err := actorID(ctx)
+	if err != nil {
+		return err
+	}
+
+	for i := range children.Len() {
+		auditable := children.Index(i).Interface().(sharedEntity.AuditableInterface)
+		auditable.SetCreated(id, now)
+		auditable.SetUpdated(id, now)
+	}
+
+	return nil
+}
+
+// auditOnConflict makes onConflict keep the created columns and update the
+// updated columns of an auditable entity.
+func auditOnConflict(s *schema.Schema, onConflict clause.OnConflict) clause.OnConflict {
+	if onConflict.UpdateAll {
+		onConflict.UpdateAll = false
+		onConflict.DoUpdates = clause.AssignmentColumns(updateColumns(s))
+		return onConflict
+	}
+
+	var columns []string
+	for _, assignment := range onConflict.DoUpdates {
+		columns = append(columns, assignment.Column.Name)
+	}
+	for _, column := range []string{sharedEntity.UpdatedByColumn, sharedEntity.UpdatedAtColumn} {
+		if !slices.Contains(columns, column) {
+			onConflict.DoUpdates = append(onConflict.DoUpdates, clause.AssignmentColumns([]string{column})...)
+		}
+	}
+	return onConflict
+}
+
+// updateColumns returns the columns an upsert updates: those gorm updates for
+// UpdateAll, plus the updated columns of an auditable entity but without its
+// created columns.
+func updateColumns(s *schema.Schema) []string {
+	var columns []string
+	for _, field := range s.Fields {
+		if field.DBName == "" || field.PrimaryKey || !field.Updatable || slices.Contains(createdColumns, field.DBName) {
+			continue
+		}
+
+		switch field.DBName {
+		case sharedEntity.UpdatedByColumn, sharedEntity.UpdatedAtColumn:
+			columns = append(columns, field.DBName)
+			continue
+		}
+
+		hasDBDefault := field.HasDefaultValue && field.DefaultValueInterface == nil && !strings.EqualFold(field.DefaultValue, "NULL")
+		if field.AutoCreateTime > 0 || hasDBDefault {
+			continue
+		}
+		columns = append(columns, field.DBName)
+	}
+	return columns
+}
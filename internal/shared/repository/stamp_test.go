@@ -0,0 +1,186 @@
+package repository
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/ming-0x0/hexago/internal/shared/actor"
+	sharedErrors "github.com/ming-0x0/hexago/internal/shared/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRepository_Stamp(t *testing.T) {
+	t.Parallel()
+
+	const actorID = "01ARZ3NDEKTSV4RRFFQ69G5FAV"
+
+	type repo = *Repository[*SoftDummyAdapter, DummyDomain, SoftDummyEntity]
+
+	tests := []struct {
+		name      string
+		ctx       context.Context
+		call      func(context.Context, repo) error
+		setupMock func(sqlmock.Sqlmock)
+		assertion assert.ErrorAssertionFunc
+		errCode   sharedErrors.ErrorCode
+	}{
+		{
+			name: "Create",
+			ctx:  actor.WithID(context.Background(), actorID),
+			call: func(ctx context.Context, r repo) error {
+				return r.Create(ctx, &DummyDomain{Name: "Test"})
+			},
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(regexp.QuoteMeta(
+					"INSERT INTO `soft_dummy_entities` (`name`,`created_by`,`updated_by`,`deleted_by`,`deleted_at`,`created_at`,`updated_at`) VALUES (?,?,?,?,?,?,?)",
+				)).WithArgs("Test", actorID, actorID, "", nil, sqlmock.AnyArg(), sqlmock.AnyArg()).WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
+			},
+			assertion: assert.NoError,
+		},
+		{
+			name: "Save_KeepsCreatedColumns",
+			ctx:  actor.WithID(context.Background(), actorID),
+			call: func(ctx context.Context, r repo) error {
+				return r.Save(ctx, &DummyDomain{ID: 1, Name: "Test"})
+			},
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(regexp.QuoteMeta(
+					"UPDATE `soft_dummy_entities` SET `name`=?,`updated_by`=?,`updated_at`=?,`deleted_by`=?,`deleted_at`=? WHERE `soft_dummy_entities`.`deleted_at` IS NULL AND `id` = ?",
+				)).WithArgs("Test", actorID, sqlmock.AnyArg(), "", nil, 1).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+			assertion: assert.NoError,
+		},
+		{
+			name: "Save_InsertsNewRow",
+			ctx:  actor.WithID(context.Background(), actorID),
+			call: func(ctx context.Context, r repo) error {
+				return r.Save(ctx, &DummyDomain{ID: 7, Name: "Test"})
+			},
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec("UPDATE `soft_dummy_entities`").WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectCommit()
+				mock.ExpectQuery(regexp.QuoteMeta(
+					"SELECT count(*) FROM `soft_dummy_entities` WHERE `soft_dummy_entities`.`id` = ? AND `soft_dummy_entities`.`deleted_at` IS NULL",
+				)).WithArgs(7).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+				mock.ExpectQuery(regexp.QuoteMeta(
+					"SELECT count(*) FROM `soft_dummy_entities` WHERE `soft_dummy_entities`.`id` = ?",
+				)).WithArgs(7).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+				mock.ExpectBegin()
+				mock.ExpectExec(regexp.QuoteMeta(
+					"INSERT INTO `soft_dummy_entities` (`name`,`created_by`,`updated_by`,`deleted_by`,`deleted_at`,`created_at`,`updated_at`,`id`) VALUES (?,?,?,?,?,?,?,?)",
+				)).WithArgs("Test", actorID, actorID, "", nil, sqlmock.AnyArg(), sqlmock.AnyArg(), 7).WillReturnResult(sqlmock.NewResult(7, 1))
+				mock.ExpectCommit()
+			},
+			assertion: assert.NoError,
+		},
+		{
+			name: "Save_UnchangedRow",
+			ctx:  actor.WithID(context.Background(), actorID),
+			call: func(ctx context.Context, r repo) error {
+				return r.Save(ctx, &DummyDomain{ID: 1, Name: "Test"})
+			},
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec("UPDATE `soft_dummy_entities`").WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectCommit()
+				mock.ExpectQuery("SELECT count").WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+			},
+			assertion: assert.NoError,
+		},
+		{
+			name: "Failure_SaveSoftDeletedRow",
+			ctx:  actor.WithID(context.Background(), actorID),
+			call: func(ctx context.Context, r repo) error {
+				return r.Save(ctx, &DummyDomain{ID: 1, Name: "Test"})
+			},
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec("UPDATE `soft_dummy_entities`").WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectCommit()
+				mock.ExpectQuery(regexp.QuoteMeta(
+					"SELECT count(*) FROM `soft_dummy_entities` WHERE `soft_dummy_entities`.`id` = ? AND `soft_dummy_entities`.`deleted_at` IS NULL",
+				)).WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+				mock.ExpectQuery(regexp.QuoteMeta(
+					"SELECT count(*) FROM `soft_dummy_entities` WHERE `soft_dummy_entities`.`id` = ?",
+				)).WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+			},
+			assertion: assert.Error,
+			errCode:   sharedErrors.NotFound,
+		},
+		{
+			name: "SaveMany_KeepsCreatedColumns",
+			ctx:  actor.WithID(context.Background(), actorID),
+			call: func(ctx context.Context, r repo) error {
+				return r.SaveMany(ctx, []*DummyDomain{{ID: 1, Name: "Test"}})
+			},
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(regexp.QuoteMeta(
+					"INSERT INTO `soft_dummy_entities` (`name`,`created_by`,`updated_by`,`deleted_by`,`deleted_at`,`created_at`,`updated_at`,`id`) VALUES (?,?,?,?,?,?,?,?) "+
+						"ON DUPLICATE KEY UPDATE `name`=VALUES(`name`),`updated_by`=VALUES(`updated_by`),`updated_at`=VALUES(`updated_at`),`deleted_by`=VALUES(`deleted_by`),`deleted_at`=VALUES(`deleted_at`)",
+				)).WithArgs("Test", actorID, actorID, "", nil, sqlmock.AnyArg(), sqlmock.AnyArg(), 1).WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
+			},
+			assertion: assert.NoError,
+		},
+		{
+			name: "Upsert_AddsUpdatedColumns",
+			ctx:  actor.WithID(context.Background(), actorID),
+			call: func(ctx context.Context, r repo) error {
+				return r.Upsert(ctx, []*DummyDomain{{ID: 1, Name: "Test"}}, OnConflict{UpdateColumns: []string{"name"}})
+			},
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(regexp.QuoteMeta(
+					"ON DUPLICATE KEY UPDATE `name`=VALUES(`name`),`updated_by`=VALUES(`updated_by`),`updated_at`=VALUES(`updated_at`)",
+				)).WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
+			},
+			assertion: assert.NoError,
+		},
+		{
+			name: "Failure_CreateWithoutActor",
+			ctx:  context.Background(),
+			call: func(ctx context.Context, r repo) error {
+				return r.Create(ctx, &DummyDomain{Name: "Test"})
+			},
+			setupMock: func(mock sqlmock.Sqlmock) {},
+			assertion: assert.Error,
+		},
+		{
+			name: "Failure_SaveManyWithoutActor",
+			ctx:  context.Background(),
+			call: func(ctx context.Context, r repo) error {
+				return r.SaveMany(ctx, []*DummyDomain{{ID: 1, Name: "Test"}})
+			},
+			setupMock: func(mock sqlmock.Sqlmock) {},
+			assertion: assert.Error,
+		},
+	}
+
+	for _, tt := range tests {
+		tc := tt
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			repo, mockedDB, sqlMock := setupSoftDeleteTest(t)
+			defer teardownTest(mockedDB)
+
+			tc.setupMock(sqlMock)
+			err := tc.call(tc.ctx, repo)
+			tc.assertion(t, err)
+			if tc.errCode != 0 {
+				var domainErr *sharedErrors.DomainError
+				assert.ErrorAs(t, err, &domainErr)
+				assert.Equal(t, tc.errCode, domainErr.ErrorCode())
+			}
+			assert.NoError(t, sqlMock.ExpectationsWereMet())
+		})
+	}
+}
@@ -0,0 +1,115 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	sharedEntity "github.com/ming-0x0/hexago/internal/shared/entity"
+	sharedErrors "github.com/ming-0x0/hexago/internal/shared/errors"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// saveVersioned updates the row of entity only if its version is still the
+// one entity was loaded with, then increments the version of domain. An
+// entity of version 0 has never been stored, so it is inserted with version 1
+// as Create does.
+func (r *Repository[A, D, E]) saveVersioned(
+	ctx context.Context,
+	domain *D,
+	entity *E,
+	versioned sharedEntity.VersionedInterface,
+) error {
+	current := versioned.CurrentVersion()
+	versioned.SetVersion(current + 1)
+
+	if current == 0 {
+		if err := r.stamp(ctx, true, entity); err != nil {
+			return err
+		}
+		if err := r.DB(ctx).Scopes(r.omitAssociations).Create(entity).Error; err != nil {
+			return sharedErrors.NewDomainError(sharedErrors.System, err.Error())
+		}
+		return r.refresh(domain, entity)
+	}
+
+	result := r.DB(ctx).
+		Model(entity).
+		Where(clause.Eq{
+			Column: clause.Column{Table: clause.CurrentTable, Name: sharedEntity.VersionColumn},
+			Value:  current,
+		}).
+		Select("*").
+		Scopes(omitCreated[E], r.omitAssociations).
+		Updates(entity)
+	if result.Error != nil {
+		return sharedErrors.NewDomainError(sharedErrors.System, result.Error.Error())
+	}
+
+	if result.RowsAffected == 0 {
+		return sharedErrors.NewDomainError(
+			sharedErrors.Conflict,
+			fmt.Sprintf("version %d is outdated or the row no longer exists", current),
+		)
+	}
+
+	return r.refresh(domain, entity)
+}
+
+func isVersioned[E any]() bool {
+	_, ok := any(new(E)).(sharedEntity.VersionedInterface)
+	return ok
+}
+
+// bumpVersions increments the versions of versioned entities about to be
+// upserted, a new entity getting version 1 as Create gives it.
+func bumpVersions[E any](entities []*E) {
+	for _, entity := range entities {
+		if versioned, ok := any(entity).(sharedEntity.VersionedInterface); ok {
+			versioned.SetVersion(versioned.CurrentVersion() + 1)
+		}
+	}
+}
+
+// versionOnConflict makes onConflict overwrite the version of the existing
+// rows of a versioned entity with the version bumped by bumpVersions, so that
+// a row always has the version of the domain written to it. The version the
+// row had is not checked, and domains loaded before the upsert can still be
+// saved over it if their version happens to match.
+func versionOnConflict(s *schema.Schema, onConflict clause.OnConflict) clause.OnConflict {
+	if onConflict.UpdateAll {
+		onConflict.UpdateAll = false
+		onConflict.DoUpdates = clause.AssignmentColumns(updateColumns(s))
+	}
+
+	assignments := make(clause.Set, 0, len(onConflict.DoUpdates)+1)
+	for _, assignment := range onConflict.DoUpdates {
+		if assignment.Column.Name != sharedEntity.VersionColumn {
+			assignments = append(assignments, assignment)
+		}
+	}
+	onConflict.DoUpdates = append(assignments, clause.AssignmentColumns([]string{sharedEntity.VersionColumn})...)
+	return onConflict
+}
+
+// refreshAll replaces the domains with the conversions of entities.
+func (r *Repository[A, D, E]) refreshAll(domains []*D, entities []*E) error {
+	for i, entity := range entities {
+		if err := r.refresh(domains[i], entity); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// refresh replaces domain with the conversion of entity, so that it carries
+// the values written by the repository such as the version.
+func (r *Repository[A, D, E]) refresh(domain *D, entity *E) error {
+	refreshed, err := r.adapter.ToDomain(entity)
+	if err != nil {
+		return err
+	}
+
+	*domain = *refreshed
+	return nil
+}
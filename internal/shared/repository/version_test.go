@@ -0,0 +1,315 @@
+package repository
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/ming-0x0/hexago/internal/shared/dbmocker"
+	sharedEntity "github.com/ming-0x0/hexago/internal/shared/entity"
+	"github.com/ming-0x0/hexago/internal/shared/errors"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+type VersionedDummyEntity struct {
+	ID   int
+	Name string
+	sharedEntity.Versioned
+}
+
+type VersionedDummyDomain struct {
+	ID      int
+	Name    string
+	Version int64
+}
+
+type VersionedDummyAdapter struct{}
+
+func (a *VersionedDummyAdapter) ToDomain(entity *VersionedDummyEntity) (*VersionedDummyDomain, error) {
+	return &VersionedDummyDomain{ID: entity.ID, Name: entity.Name, Version: entity.Version}, nil
+}
+
+func (a *VersionedDummyAdapter) ToEntity(domain *VersionedDummyDomain) (*VersionedDummyEntity, error) {
+	return &VersionedDummyEntity{
+		ID:        domain.ID,
+		Name:      domain.Name,
+		Versioned: sharedEntity.Versioned{Version: domain.Version},
+	}, nil
+}
+
+func (a *VersionedDummyAdapter) ToDomains(entities []*VersionedDummyEntity) ([]*VersionedDummyDomain, error) {
+	domains := make([]*VersionedDummyDomain, 0, len(entities))
+	for _, e := range entities {
+		d, _ := a.ToDomain(e)
+		domains = append(domains, d)
+	}
+	return domains, nil
+}
+
+func (a *VersionedDummyAdapter) ToEntities(domains []*VersionedDummyDomain) ([]*VersionedDummyEntity, error) {
+	entities := make([]*VersionedDummyEntity, 0, len(domains))
+	for _, d := range domains {
+		e, _ := a.ToEntity(d)
+		entities = append(entities, e)
+	}
+	return entities, nil
+}
+
+func setupVersionedTest(t *testing.T) (*Repository[*VersionedDummyAdapter, VersionedDummyDomain, VersionedDummyEntity], *dbmocker.MockedRepository, sqlmock.Sqlmock) {
+	mockedDB, err := dbmocker.NewMockedDB()
+	if err != nil {
+		t.Fatalf("error when creating mock DB: %v", err)
+	}
+
+	repo := NewRepository(mockedDB.GormDB, mockedDB.Logger, &VersionedDummyAdapter{})
+	return repo, mockedDB, mockedDB.SqlMock
+}
+
+func TestRepository_Create_Versioned(t *testing.T) {
+	t.Parallel()
+
+	repo, mockedDB, sqlMock := setupVersionedTest(t)
+	defer teardownTest(mockedDB)
+
+	sqlMock.ExpectBegin()
+	sqlMock.ExpectExec(regexp.QuoteMeta("INSERT INTO `versioned_dummy_entities` (`name`,`version`,`id`) VALUES (?,?,?)")).
+		WithArgs("Test", 1, 1).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	sqlMock.ExpectCommit()
+
+	domain := &VersionedDummyDomain{ID: 1, Name: "Test"}
+	err := repo.Create(context.Background(), domain)
+	assert.NoError(t, err)
+	assert.Equal(t, &VersionedDummyDomain{ID: 1, Name: "Test", Version: 1}, domain)
+}
+
+func TestRepository_Save_Versioned(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		version   int64
+		setupMock func(sqlmock.Sqlmock)
+		assertion assert.ErrorAssertionFunc
+		errCode   errors.ErrorCode
+		expected  *VersionedDummyDomain
+	}{
+		{
+			name:    "Success",
+			version: 2,
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(regexp.QuoteMeta(
+					"UPDATE `versioned_dummy_entities` SET `name`=?,`version`=? WHERE `versioned_dummy_entities`.`version` = ? AND `id` = ?",
+				)).WithArgs("Updated", 3, 2, 1).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+			assertion: assert.NoError,
+			expected:  &VersionedDummyDomain{ID: 1, Name: "Updated", Version: 3},
+		},
+		{
+			name:    "Success_NewEntityInserted",
+			version: 0,
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `versioned_dummy_entities` (`name`,`version`,`id`) VALUES (?,?,?)")).
+					WithArgs("Updated", 1, 1).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
+			},
+			assertion: assert.NoError,
+			expected:  &VersionedDummyDomain{ID: 1, Name: "Updated", Version: 1},
+		},
+		{
+			name:    "Failure_NewEntityExists",
+			version: 0,
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec("INSERT INTO `versioned_dummy_entities`").WillReturnError(gorm.ErrDuplicatedKey)
+				mock.ExpectRollback()
+			},
+			assertion: assert.Error,
+			errCode:   errors.System,
+			expected:  &VersionedDummyDomain{ID: 1, Name: "Updated", Version: 0},
+		},
+		{
+			name:    "Failure_VersionMoved",
+			version: 2,
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec("UPDATE `versioned_dummy_entities`").
+					WithArgs("Updated", 3, 2, 1).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectCommit()
+			},
+			assertion: assert.Error,
+			errCode:   errors.Conflict,
+			expected:  &VersionedDummyDomain{ID: 1, Name: "Updated", Version: 2},
+		},
+		{
+			name:    "Failure_DBError",
+			version: 2,
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec("UPDATE `versioned_dummy_entities`").WillReturnError(gorm.ErrInvalidData)
+				mock.ExpectRollback()
+			},
+			assertion: assert.Error,
+			errCode:   errors.System,
+			expected:  &VersionedDummyDomain{ID: 1, Name: "Updated", Version: 2},
+		},
+	}
+
+	for _, tt := range tests {
+		tc := tt
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			repo, mockedDB, sqlMock := setupVersionedTest(t)
+			defer teardownTest(mockedDB)
+
+			tc.setupMock(sqlMock)
+			domain := &VersionedDummyDomain{ID: 1, Name: "Updated", Version: tc.version}
+			err := repo.Save(context.Background(), domain)
+			tc.assertion(t, err)
+			if err != nil {
+				var domainErr *errors.DomainError
+				assert.ErrorAs(t, err, &domainErr)
+				assert.Equal(t, tc.errCode, domainErr.ErrorCode())
+			}
+			assert.Equal(t, tc.expected, domain)
+		})
+	}
+}
+
+func TestRepository_SaveMany_Versioned(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		setupMock func(sqlmock.Sqlmock)
+		assertion assert.ErrorAssertionFunc
+		expected  []*VersionedDummyDomain
+	}{
+		{
+			name: "Success",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(regexp.QuoteMeta(
+					"INSERT INTO `versioned_dummy_entities` (`name`,`version`,`id`) VALUES (?,?,?),(?,?,?) "+
+						"ON DUPLICATE KEY UPDATE `name`=VALUES(`name`),`version`=VALUES(`version`)",
+				)).WithArgs("New", 1, 1, "Loaded", 3, 2).WillReturnResult(sqlmock.NewResult(2, 3))
+				mock.ExpectCommit()
+			},
+			assertion: assert.NoError,
+			expected: []*VersionedDummyDomain{
+				{ID: 1, Name: "New", Version: 1},
+				{ID: 2, Name: "Loaded", Version: 3},
+			},
+		},
+		{
+			name: "Failure_DBError",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec("INSERT INTO `versioned_dummy_entities`").WillReturnError(gorm.ErrInvalidData)
+				mock.ExpectRollback()
+			},
+			assertion: assert.Error,
+			expected: []*VersionedDummyDomain{
+				{ID: 1, Name: "New", Version: 0},
+				{ID: 2, Name: "Loaded", Version: 2},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tc := tt
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			repo, mockedDB, sqlMock := setupVersionedTest(t)
+			defer teardownTest(mockedDB)
+
+			tc.setupMock(sqlMock)
+			domains := []*VersionedDummyDomain{
+				{ID: 1, Name: "New"},
+				{ID: 2, Name: "Loaded", Version: 2},
+			}
+			err := repo.SaveMany(context.Background(), domains)
+			tc.assertion(t, err)
+			assert.Equal(t, tc.expected, domains)
+			assert.NoError(t, sqlMock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestRepository_Upsert_Versioned(t *testing.T) {
+	t.Parallel()
+
+	repo, mockedDB, sqlMock := setupVersionedTest(t)
+	defer teardownTest(mockedDB)
+
+	sqlMock.ExpectBegin()
+	sqlMock.ExpectExec(regexp.QuoteMeta(
+		"INSERT INTO `versioned_dummy_entities` (`name`,`version`,`id`) VALUES (?,?,?) "+
+			"ON DUPLICATE KEY UPDATE `name`=VALUES(`name`),`version`=VALUES(`version`)",
+	)).WithArgs("Loaded", 3, 1).WillReturnResult(sqlmock.NewResult(1, 2))
+	sqlMock.ExpectCommit()
+
+	domains := []*VersionedDummyDomain{{ID: 1, Name: "Loaded", Version: 2}}
+	err := repo.Upsert(context.Background(), domains, OnConflict{UpdateColumns: []string{"name", sharedEntity.VersionColumn}})
+	assert.NoError(t, err)
+	assert.Equal(t, []*VersionedDummyDomain{{ID: 1, Name: "Loaded", Version: 3}}, domains)
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+func TestRepository_Upsert_VersionedExistingKey(t *testing.T) {
+	t.Parallel()
+
+	repo, mockedDB, sqlMock := setupVersionedTest(t)
+	defer teardownTest(mockedDB)
+
+	// MySQL reports 2 affected rows when the row already exists and is updated.
+	sqlMock.ExpectBegin()
+	sqlMock.ExpectExec(regexp.QuoteMeta(
+		"INSERT INTO `versioned_dummy_entities` (`name`,`version`,`id`) VALUES (?,?,?) "+
+			"ON DUPLICATE KEY UPDATE `name`=VALUES(`name`),`version`=VALUES(`version`)",
+	)).WithArgs("New", 1, 1).WillReturnResult(sqlmock.NewResult(1, 2))
+	sqlMock.ExpectCommit()
+	sqlMock.ExpectBegin()
+	sqlMock.ExpectExec(regexp.QuoteMeta(
+		"UPDATE `versioned_dummy_entities` SET `name`=?,`version`=? WHERE `versioned_dummy_entities`.`version` = ? AND `id` = ?",
+	)).WithArgs("Updated", 2, 1, 1).WillReturnResult(sqlmock.NewResult(0, 1))
+	sqlMock.ExpectCommit()
+
+	domains := []*VersionedDummyDomain{{ID: 1, Name: "New"}}
+	err := repo.Upsert(context.Background(), domains, OnConflict{UpdateColumns: []string{"name"}})
+	assert.NoError(t, err)
+	assert.Equal(t, []*VersionedDummyDomain{{ID: 1, Name: "New", Version: 1}}, domains)
+
+	// the row has the version of the domain, which can be saved over it.
+	domains[0].Name = "Updated"
+	err = repo.Save(context.Background(), domains[0])
+	assert.NoError(t, err)
+	assert.Equal(t, &VersionedDummyDomain{ID: 1, Name: "Updated", Version: 2}, domains[0])
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+func TestRepository_CreateMany_Versioned(t *testing.T) {
+	t.Parallel()
+
+	repo, mockedDB, sqlMock := setupVersionedTest(t)
+	defer teardownTest(mockedDB)
+
+	sqlMock.ExpectBegin()
+	sqlMock.ExpectExec(regexp.QuoteMeta("INSERT INTO `versioned_dummy_entities` (`name`,`version`,`id`) VALUES (?,?,?)")).
+		WithArgs("New", 1, 1).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	sqlMock.ExpectCommit()
+
+	domains := []*VersionedDummyDomain{{ID: 1, Name: "New"}}
+	err := repo.CreateMany(context.Background(), domains)
+	assert.NoError(t, err)
+	assert.Equal(t, []*VersionedDummyDomain{{ID: 1, Name: "New", Version: 1}}, domains)
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
@@ -0,0 +1,84 @@
+package specification
+
+import (
+	"strings"
+
+	"gorm.io/gorm/clause"
+)
+
+// Order sorts the result by a column.
+type Order struct {
+	Column string `json:"column"`
+	Desc   bool   `json:"desc"`
+}
+
+// Asc sorts by column in ascending order.
+func Asc(column string) Order {
+	return Order{Column: column}
+}
+
+// Desc sorts by column in descending order.
+func Desc(column string) Order {
+	return Order{Column: column, Desc: true}
+}
+
+// ParseOrders parses comma separated columns, each in descending order when
+// prefixed with "-", such as "customer_name,-created_at".
+func ParseOrders(s string) []Order {
+	var orders []Order
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if column, ok := strings.CutPrefix(part, "-"); ok {
+			orders = append(orders, Desc(strings.TrimSpace(column)))
+		} else if part != "" {
+			orders = append(orders, Asc(part))
+		}
+	}
+	return orders
+}
+
+// Clause returns the gorm order by clause of orders, or nil when orders is empty.
+func Clause(orders []Order) clause.Expression {
+	if len(orders) == 0 {
+		return nil
+	}
+
+	columns := make([]clause.OrderByColumn, 0, len(orders))
+	for _, order := range orders {
+		columns = append(columns, clause.OrderByColumn{Column: column(order.Column), Desc: order.Desc})
+	}
+	return clause.OrderBy{Columns: columns}
+}
+
+type ordered struct {
+	Specification
+	orders []Order
+}
+
+// OrderBy sorts the rows matched by spec, which may be nil to sort every row.
+// It must wrap the outermost specification, the orders of nested specifications are ignored.
+func OrderBy(spec Specification, orders ...Order) Specification {
+	return ordered{Specification: spec, orders: orders}
+}
+
+func (o ordered) Columns() []string {
+	if o.Specification == nil {
+		return nil
+	}
+	return o.Specification.Columns()
+}
+
+func (o ordered) Expression() clause.Expression {
+	if o.Specification == nil {
+		return nil
+	}
+	return o.Specification.Expression()
+}
+
+// Orders returns the orders of spec given by OrderBy.
+func Orders(spec Specification) []Order {
+	if o, ok := spec.(ordered); ok {
+		return o.orders
+	}
+	return nil
+}
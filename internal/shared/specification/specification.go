@@ -0,0 +1,195 @@
+package specification
+
+import (
+	"fmt"
+
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// Specification is a query condition on the columns of an entity.
+type Specification interface {
+	// Columns returns the columns the specification refers to.
+	Columns() []string
+	// Expression returns the gorm clause of the specification, or nil when it has no condition.
+	Expression() clause.Expression
+}
+
+// Validate checks that every column of spec is a column of the entity described by s.
+func Validate(spec Specification, s *schema.Schema) error {
+	if spec == nil {
+		return nil
+	}
+
+	for _, column := range spec.Columns() {
+		if _, ok := s.FieldsByDBName[column]; !ok {
+			return fmt.Errorf("unknown column %q of table %s", column, s.Table)
+		}
+	}
+
+	for _, order := range Orders(spec) {
+		if _, ok := s.FieldsByDBName[order.Column]; !ok {
+			return fmt.Errorf("unknown order column %q of table %s", order.Column, s.Table)
+		}
+	}
+
+	return nil
+}
+
+func column(name string) clause.Column {
+	return clause.Column{Table: clause.CurrentTable, Name: name}
+}
+
+type condition struct {
+	column     string
+	expression clause.Expression
+}
+
+func (c condition) Columns() []string {
+	return []string{c.column}
+}
+
+func (c condition) Expression() clause.Expression {
+	return c.expression
+}
+
+// Eq matches rows whose column equals value.
+func Eq(name string, value any) Specification {
+	return condition{column: name, expression: clause.Eq{Column: column(name), Value: value}}
+}
+
+// Neq matches rows whose column does not equal value.
+func Neq(name string, value any) Specification {
+	return condition{column: name, expression: clause.Neq{Column: column(name), Value: value}}
+}
+
+// Gt matches rows whose column is greater than value.
+func Gt(name string, value any) Specification {
+	return condition{column: name, expression: clause.Gt{Column: column(name), Value: value}}
+}
+
+// Gte matches rows whose column is greater than or equal to value.
+func Gte(name string, value any) Specification {
+	return condition{column: name, expression: clause.Gte{Column: column(name), Value: value}}
+}
+
+// Lt matches rows whose column is less than value.
+func Lt(name string, value any) Specification {
+	return condition{column: name, expression: clause.Lt{Column: column(name), Value: value}}
+}
+
+// Lte matches rows whose column is less than or equal to value.
+func Lte(name string, value any) Specification {
+	return condition{column: name, expression: clause.Lte{Column: column(name), Value: value}}
+}
+
+// In matches rows whose column is one of values.
+func In[T any](name string, values ...T) Specification {
+	vars := make([]any, 0, len(values))
+	for _, value := range values {
+		vars = append(vars, value)
+	}
+	return condition{column: name, expression: clause.IN{Column: column(name), Values: vars}}
+}
+
+// Between matches rows whose column is between from and to, inclusive.
+func Between(name string, from, to any) Specification {
+	return condition{column: name, expression: clause.Expr{
+		SQL:  "? BETWEEN ? AND ?",
+		Vars: []any{column(name), from, to},
+	}}
+}
+
+// Like matches rows whose column matches the LIKE pattern.
+func Like(name string, pattern string) Specification {
+	return condition{column: name, expression: clause.Like{Column: column(name), Value: pattern}}
+}
+
+// IsNull matches rows whose column is NULL.
+func IsNull(name string) Specification {
+	return condition{column: name, expression: clause.Eq{Column: column(name), Value: nil}}
+}
+
+// IsNotNull matches rows whose column is not NULL.
+func IsNotNull(name string) Specification {
+	return condition{column: name, expression: clause.Neq{Column: column(name), Value: nil}}
+}
+
+type composite struct {
+	specs   []Specification
+	combine func(...clause.Expression) clause.Expression
+}
+
+func (c composite) Columns() []string {
+	var columns []string
+	for _, spec := range c.specs {
+		if spec != nil {
+			columns = append(columns, spec.Columns()...)
+		}
+	}
+	return columns
+}
+
+func (c composite) Expression() clause.Expression {
+	var exprs []clause.Expression
+	for _, spec := range c.specs {
+		if spec == nil {
+			continue
+		}
+		if expr := spec.Expression(); expr != nil {
+			exprs = append(exprs, expr)
+		}
+	}
+
+	switch len(exprs) {
+	case 0:
+		return nil
+	case 1:
+		return exprs[0]
+	}
+	return c.combine(exprs...)
+}
+
+// And matches rows matching all of specs. nil specs are ignored.
+func And(specs ...Specification) Specification {
+	return composite{specs: specs, combine: clause.And}
+}
+
+// Or matches rows matching any of specs. nil specs are ignored.
+func Or(specs ...Specification) Specification {
+	return composite{specs: specs, combine: clause.Or}
+}
+
+type not struct {
+	spec Specification
+}
+
+func (n not) Columns() []string {
+	if n.spec == nil {
+		return nil
+	}
+	return n.spec.Columns()
+}
+
+func (n not) Expression() clause.Expression {
+	if n.spec == nil {
+		return nil
+	}
+	expr := n.spec.Expression()
+	if expr == nil {
+		return nil
+	}
+	// clause.Not negates each condition of an AND group separately, which is
+	// not the negation of the group, so the whole expression is negated instead.
+	// AND and OR groups of several conditions are already parenthesized.
+	switch expr.(type) {
+	case clause.AndConditions, clause.OrConditions:
+		return clause.Expr{SQL: "NOT ?", Vars: []any{expr}}
+	}
+	return clause.Expr{SQL: "NOT (?)", Vars: []any{expr}}
+}
+
+// Not matches rows not matching spec.
+func Not(spec Specification) Specification {
+	return not{spec: spec}
+}
@@ -0,0 +1,225 @@
+package specification
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/ming-0x0/hexago/internal/shared/dbmocker"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+type DummyEntity struct {
+	ID        int    `gorm:"column:id;primaryKey"`
+	Name      string `gorm:"column:name"`
+	Status    int    `gorm:"column:status"`
+	DeletedBy string `gorm:"column:deleted_by"`
+}
+
+// buildSQL renders the query selecting the rows of DummyEntity matching spec.
+func buildSQL(t *testing.T, spec Specification) (string, []any) {
+	t.Helper()
+
+	mockedDB, err := dbmocker.NewMockedDB()
+	if err != nil {
+		t.Fatalf("error when creating mock DB: %v", err)
+	}
+	defer mockedDB.DB.Close()
+
+	db := mockedDB.GormDB.Session(&gorm.Session{DryRun: true}).Model(&DummyEntity{})
+	if expr := spec.Expression(); expr != nil {
+		db = db.Where(expr)
+	}
+	if orderBy := Clause(Orders(spec)); orderBy != nil {
+		db = db.Clauses(orderBy)
+	}
+	stmt := db.Find(&[]*DummyEntity{}).Statement
+	return stmt.SQL.String(), stmt.Vars
+}
+
+func TestSpecification_Expression(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		spec     Specification
+		expected string
+		vars     []any
+	}{
+		{
+			name:     "Eq",
+			spec:     Eq("name", "Test"),
+			expected: "SELECT * FROM `dummy_entities` WHERE `dummy_entities`.`name` = ?",
+			vars:     []any{"Test"},
+		},
+		{
+			name:     "Neq",
+			spec:     Neq("status", 1),
+			expected: "SELECT * FROM `dummy_entities` WHERE `dummy_entities`.`status` <> ?",
+			vars:     []any{1},
+		},
+		{
+			name:     "GtLte",
+			spec:     And(Gt("id", 1), Lte("id", 10)),
+			expected: "SELECT * FROM `dummy_entities` WHERE `dummy_entities`.`id` > ? AND `dummy_entities`.`id` <= ?",
+			vars:     []any{1, 10},
+		},
+		{
+			name:     "In",
+			spec:     In("status", 1, 2),
+			expected: "SELECT * FROM `dummy_entities` WHERE `dummy_entities`.`status` IN (?,?)",
+			vars:     []any{1, 2},
+		},
+		{
+			name:     "Between",
+			spec:     Between("id", 1, 10),
+			expected: "SELECT * FROM `dummy_entities` WHERE `dummy_entities`.`id` BETWEEN ? AND ?",
+			vars:     []any{1, 10},
+		},
+		{
+			name:     "IsNull",
+			spec:     IsNull("deleted_by"),
+			expected: "SELECT * FROM `dummy_entities` WHERE `dummy_entities`.`deleted_by` IS NULL",
+		},
+		{
+			name:     "IsNotNull",
+			spec:     IsNotNull("deleted_by"),
+			expected: "SELECT * FROM `dummy_entities` WHERE `dummy_entities`.`deleted_by` IS NOT NULL",
+		},
+		{
+			name:     "AndOr",
+			spec:     And(Eq("status", 1), Or(Like("name", "A%"), Like("name", "B%"))),
+			expected: "SELECT * FROM `dummy_entities` WHERE `dummy_entities`.`status` = ? AND (`dummy_entities`.`name` LIKE ? OR `dummy_entities`.`name` LIKE ?)",
+			vars:     []any{1, "A%", "B%"},
+		},
+		{
+			name:     "Not",
+			spec:     Not(And(Eq("status", 1), Eq("name", "Test"))),
+			expected: "SELECT * FROM `dummy_entities` WHERE NOT (`dummy_entities`.`status` = ? AND `dummy_entities`.`name` = ?)",
+			vars:     []any{1, "Test"},
+		},
+		{
+			name:     "Not_Or",
+			spec:     Not(Or(Eq("status", 1), Eq("status", 2))),
+			expected: "SELECT * FROM `dummy_entities` WHERE NOT (`dummy_entities`.`status` = ? OR `dummy_entities`.`status` = ?)",
+			vars:     []any{1, 2},
+		},
+		{
+			name:     "Not_Single",
+			spec:     Not(Like("name", "A%")),
+			expected: "SELECT * FROM `dummy_entities` WHERE NOT (`dummy_entities`.`name` LIKE ?)",
+			vars:     []any{"A%"},
+		},
+		{
+			name:     "And_NilIgnored",
+			spec:     And(nil, Eq("id", 1), Or()),
+			expected: "SELECT * FROM `dummy_entities` WHERE `dummy_entities`.`id` = ?",
+			vars:     []any{1},
+		},
+		{
+			name:     "And_Empty",
+			spec:     And(),
+			expected: "SELECT * FROM `dummy_entities`",
+		},
+		{
+			name:     "OrderBy",
+			spec:     OrderBy(Eq("status", 1), Desc("id"), Asc("name")),
+			expected: "SELECT * FROM `dummy_entities` WHERE `dummy_entities`.`status` = ? ORDER BY `dummy_entities`.`id` DESC,`dummy_entities`.`name`",
+			vars:     []any{1},
+		},
+	}
+
+	for _, tt := range tests {
+		tc := tt
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			sql, vars := buildSQL(t, tc.spec)
+			assert.Equal(t, tc.expected, sql)
+			assert.Equal(t, tc.vars, vars)
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	t.Parallel()
+
+	s, err := schema.Parse(&DummyEntity{}, &sync.Map{}, schema.NamingStrategy{})
+	if err != nil {
+		t.Fatalf("error when parsing schema: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		spec      Specification
+		assertion assert.ErrorAssertionFunc
+	}{
+		{
+			name:      "Nil",
+			spec:      nil,
+			assertion: assert.NoError,
+		},
+		{
+			name:      "KnownColumns",
+			spec:      OrderBy(And(Eq("id", 1), Not(IsNull("deleted_by"))), Asc("name")),
+			assertion: assert.NoError,
+		},
+		{
+			name:      "FieldNameInsteadOfColumn",
+			spec:      Eq("DeletedBy", "01ARZ3NDEKTSV4RRFFQ69G5FAV"),
+			assertion: assert.Error,
+		},
+		{
+			name:      "UnknownNestedColumn",
+			spec:      Or(Eq("id", 1), Not(Eq("nmae", "Test"))),
+			assertion: assert.Error,
+		},
+		{
+			name:      "UnknownOrderColumn",
+			spec:      OrderBy(Eq("id", 1), Desc("created_at")),
+			assertion: assert.Error,
+		},
+	}
+
+	for _, tt := range tests {
+		tc := tt
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			tc.assertion(t, Validate(tc.spec, s))
+		})
+	}
+}
+
+func TestParseOrders(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		input    string
+		expected []Order
+	}{
+		{
+			name:     "Empty",
+			input:    "",
+			expected: nil,
+		},
+		{
+			name:     "AscAndDesc",
+			input:    "customer_name,-created_at",
+			expected: []Order{Asc("customer_name"), Desc("created_at")},
+		},
+		{
+			name:     "SpacesAndEmptyParts",
+			input:    " status , ,- created_at ",
+			expected: []Order{Asc("status"), Desc("created_at")},
+		},
+	}
+
+	for _, tt := range tests {
+		tc := tt
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tc.expected, ParseOrders(tc.input))
+		})
+	}
+}
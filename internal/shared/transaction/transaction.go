@@ -2,6 +2,7 @@ package transaction
 
 import (
 	"context"
+	"sync"
 
 	"gorm.io/gorm"
 )
@@ -10,6 +11,8 @@ type TxKey string
 
 const (
 	Tx TxKey = "tx"
+
+	afterCommit TxKey = "after_commit"
 )
 
 //go:generate go tool mockgen -destination mock/transaction.go -package mock github.com/ming-0x0/hexago/internal/shared/transaction TransactionInterface
@@ -28,10 +31,52 @@ func NewTransaction(db *gorm.DB) *Transaction {
 }
 
 func (t *Transaction) Do(ctx context.Context, fn func(ctx context.Context) error) error {
-	return t.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+	hooks := &commitHooks{}
+	err := t.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
 		ctx = context.WithValue(ctx, Tx, tx)
+		ctx = context.WithValue(ctx, afterCommit, hooks)
 		return fn(ctx)
 	})
+	if err != nil {
+		return err
+	}
+
+	hooks.run()
+	return nil
+}
+
+// commitHooks are the functions to run once a transaction is committed.
+type commitHooks struct {
+	mu  sync.Mutex
+	fns []func()
+}
+
+func (h *commitHooks) add(fn func()) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.fns = append(h.fns, fn)
+}
+
+func (h *commitHooks) run() {
+	h.mu.Lock()
+	fns := h.fns
+	h.fns = nil
+	h.mu.Unlock()
+
+	for _, fn := range fns {
+		fn()
+	}
+}
+
+// AfterCommit runs fn once the transaction of ctx started by Do is committed,
+// and never if it is rolled back. fn runs at once when ctx has no such
+// transaction.
+func AfterCommit(ctx context.Context, fn func()) {
+	if hooks, ok := ctx.Value(afterCommit).(*commitHooks); ok {
+		hooks.add(fn)
+		return
+	}
+	fn()
 }
 
 func TransactionFromContext(ctx context.Context) (*gorm.DB, bool) {
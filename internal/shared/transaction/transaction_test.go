@@ -110,3 +110,58 @@ func TestTransactionFromContext(t *testing.T) {
 		assert.Nil(t, retrievedTx)
 	})
 }
+
+func TestAfterCommit(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		setupMock func(sqlmock.Sqlmock)
+		err       error
+		expected  []string
+	}{
+		{
+			name: "Committed",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectCommit()
+			},
+			expected: []string{"in transaction", "after commit"},
+		},
+		{
+			name: "RolledBack",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectRollback()
+			},
+			err:      gorm.ErrInvalidTransaction,
+			expected: []string{"in transaction"},
+		},
+	}
+
+	for _, tt := range tests {
+		tc := tt
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			tx, mockedDB, _, sqlMock := setupTest(t)
+			defer teardownTest(mockedDB)
+
+			tc.setupMock(sqlMock)
+			var calls []string
+			err := tx.Do(context.Background(), func(ctx context.Context) error {
+				AfterCommit(ctx, func() { calls = append(calls, "after commit") })
+				calls = append(calls, "in transaction")
+				return tc.err
+			})
+			assert.ErrorIs(t, err, tc.err)
+			assert.Equal(t, tc.expected, calls)
+		})
+	}
+
+	t.Run("NoTransaction", func(t *testing.T) {
+		t.Parallel()
+		called := false
+		AfterCommit(context.Background(), func() { called = true })
+		assert.True(t, called)
+	})
+}
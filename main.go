@@ -1,362 +1,136 @@
 package main
 
 import (
-	"bytes"
+	"errors"
 	"flag"
 	"fmt"
-	"go/ast"
-	"go/build"
-	"go/format"
-	"go/parser"
-	"go/token"
 	"io/ioutil"
 	"log"
 	"os"
 	"path/filepath"
-	"regexp"
 	"strings"
 
-	"golang.org/x/text/cases"
-	"golang.org/x/text/language"
-	"golang.org/x/tools/imports"
+	"github.com/ming-0x0/hexago/accessor"
 )
 
+//go:generate go run . ./internal/...
 func main() {
 	log.SetPrefix("accessor: ")
 
 	var typeName, output string
-	var setter bool
-	flag.StringVar(&typeName, "type", "", "type name")
+	var opts accessor.Options
+	var w writer
+	flag.StringVar(&typeName, "type", "", "comma-separated list of type names. default: types annotated with "+accessor.MarkerComment)
 	flag.StringVar(&output, "output", "", "output file name. default: srcdir/{type}_accessor.go")
-	flag.BoolVar(&setter, "setter", false, "generate setter")
+	flag.BoolVar(&w.check, "check", false, "report generated files that are out of date instead of writing them")
+	opts.RegisterFlags(flag.CommandLine)
 	flag.Parse()
 
-	if typeName == "" {
+	cfg := accessor.Config{
+		Patterns: flag.Args(),
+		Options:  opts,
+		Output:   output,
+	}
+	if typeName != "" {
+		cfg.Types = splitTypeNames(typeName)
+		cfg.Command = strings.Join(headerArgs(os.Args[1:]), " ")
+	} else if output != "" {
+		log.Print("-output requires -type")
 		flag.Usage()
 		os.Exit(2)
 	}
 
-	args := flag.Args()
-	if len(args) == 0 {
-		args = []string{"."}
-	}
-
-	gen := generator{
-		cmdArgs:    os.Args[1:],
-		withSetter: setter,
-	}
-	var dir string
-	if len(args) == 1 && isDirectory(args[0]) {
-		dir = args[0]
-		gen.parsePackageDir(args[0])
-	} else {
-		dir = filepath.Dir(args[0])
-		gen.parsePackageFiles(args)
-	}
-
-	if output == "" {
-		output = filepath.Join(dir, fmt.Sprintf("%s_accessor.go", camelToSnake(typeName)))
+	files, err := accessor.GenerateFiles(cfg)
+	if errors.Is(err, accessor.ErrNoAnnotatedTypes) {
+		log.Print(err)
+		flag.Usage()
+		os.Exit(2)
 	}
-
-	data, err := imports.Process(output, gen.generate(typeName), nil)
 	if err != nil {
-		log.Fatalf("go import failed: %s", err)
-	}
-
-	if err := ioutil.WriteFile(output, data, 0644); err != nil {
-		log.Fatalf("write file(%s) failed: %s", output, err)
+		log.Fatal(err)
 	}
-}
-
-func isDirectory(name string) bool {
-	info, err := os.Stat(name)
-	if err != nil {
-		log.Fatalf("os stat failed:%s", err)
+	for _, file := range files {
+		w.write(relativePath(file.Path), file.Source)
 	}
-	return info.IsDir()
-}
-
-type generator struct {
-	cmdArgs    []string
-	withSetter bool
-	buf        bytes.Buffer
-	pkg        *pkg
-}
-
-func (g *generator) Printf(format string, args ...interface{}) {
-	fmt.Fprintf(&g.buf, format, args...)
-}
-
-type file struct {
-	pkg  *pkg
-	file *ast.File
-}
-
-type pkg struct {
-	dir   string
-	name  string
-	files []*file
+	w.exit()
 }
 
-func (g *generator) parsePackageDir(dir string) {
-	pkg, err := build.Default.ImportDir(dir, 0)
-	if err != nil {
-		log.Fatalf("import dir(%s) failed:%s", dir, err)
-	}
-
+// splitTypeNames splits a comma-separated type list, keeping the commas of
+// generic type arguments such as Pair[K, V] intact.
+func splitTypeNames(s string) []string {
 	var names []string
-	names = append(names, pkg.GoFiles...)
-	names = append(names, pkg.CgoFiles...)
-
-	g.parsePackage(dir, makePath(dir, names))
-}
-
-func (g *generator) parsePackageFiles(names []string) {
-	g.parsePackage(".", names)
-}
-
-func makePath(dir string, names []string) []string {
-	if dir == "." {
-		return names
-	}
-	ret := make([]string, len(names))
-	for i, name := range names {
-		ret[i] = filepath.Join(dir, name)
-	}
-	return ret
-}
-
-func (g *generator) parsePackage(dir string, names []string) {
-	var files []*file
-	var astFiles []*ast.File
-	g.pkg = new(pkg)
-	fs := token.NewFileSet()
-	for _, name := range names {
-		if !strings.HasSuffix(name, ".go") {
-			continue
-		}
-		parsedFile, err := parser.ParseFile(fs, name, nil, parser.ParseComments)
-		if err != nil {
-			log.Fatalf("parse package %s failed: %s", name, err)
+	depth, start := 0, 0
+	for i, r := range s {
+		switch r {
+		case '[':
+			depth++
+		case ']':
+			depth--
+		case ',':
+			if depth == 0 {
+				names = append(names, strings.TrimSpace(s[start:i]))
+				start = i + 1
+			}
 		}
-		astFiles = append(astFiles, parsedFile)
-		files = append(files, &file{
-			file: parsedFile,
-			pkg:  g.pkg,
-		})
-	}
-	if len(astFiles) == 0 {
-		log.Fatalf("no buildable go files:%s", dir)
 	}
-	g.pkg.name = astFiles[0].Name.Name
-	g.pkg.files = files
-	g.pkg.dir = dir
+	names = append(names, strings.TrimSpace(s[start:]))
+	return names
 }
 
-func (g *generator) generate(typeName string) []byte {
-	var stype *ast.StructType
-	var typeParams *ast.FieldList
-	baseTypeName := strings.Split(typeName, "[")[0]
-
-	for _, file := range g.pkg.files {
-		if file.file == nil {
+// headerArgs drops the flags that do not affect the generated code, so that
+// checking a file reproduces the header it was generated with.
+func headerArgs(args []string) []string {
+	var ret []string
+	for _, arg := range args {
+		switch strings.TrimLeft(arg, "-") {
+		case "check", "check=true", "check=false":
 			continue
 		}
-
-		ast.Inspect(file.file, func(node ast.Node) bool {
-			decl, ok := node.(*ast.GenDecl)
-			if !ok || decl.Tok != token.TYPE {
-				return true
-			}
-			for _, spec := range decl.Specs {
-				tspec := spec.(*ast.TypeSpec)
-				if tspec.Name.Name != baseTypeName {
-					continue
-				}
-				styp, ok := tspec.Type.(*ast.StructType)
-				if ok {
-					stype = styp
-					typeParams = tspec.TypeParams
-					return false
-				}
-			}
-			return true
-		})
-	}
-
-	if stype == nil {
-		log.Fatalf("not exist type %s", typeName)
-	}
-
-	g.Printf("// Code generated by \"accessor %s\"; DO NOT EDIT.\n\n", strings.Join(g.cmdArgs, " "))
-	g.Printf("package %s\n", g.pkg.name)
-
-	g.build(baseTypeName, stype, typeParams)
-
-	src, err := format.Source(g.buf.Bytes())
-	if err != nil {
-		log.Fatalf("format source failed:%s", err)
+		ret = append(ret, arg)
 	}
-	g.buf.Reset()
-	return src
-}
-
-const (
-	getterFormat = `// %[2]s return %[3]s value
-func (t %[1]s) %[2]s() %[4]s {
-	return t.%[3]s
-}
-`
-	setterFormat = `// Set%[2]s set v to %[3]s
-func (t *%[1]s) Set%[2]s(v %[4]s) {
-	t.%[3]s = v
+	return ret
 }
-`
-)
-
-func (g *generator) build(typeName string, typ *ast.StructType, typeParams *ast.FieldList) {
-	if typ.Fields.NumFields() == 0 {
-		log.Fatal("struct has no field")
-	}
-	exist := false
-
-	typeGenericSig := ""
-	if typeParams != nil && len(typeParams.List) > 0 {
-		var params []string
-		for _, p := range typeParams.List {
-			var names []string
-			for _, name := range p.Names {
-				names = append(names, name.Name)
-			}
-			constraint := toTypeString(p.Type)
-			params = append(params, fmt.Sprintf("%s %s", strings.Join(names, ", "), constraint))
-		}
-		typeGenericSig = "[" + strings.Join(params, ", ") + "]"
-	}
-
-	for _, field := range typ.Fields.List {
-		for _, name := range field.Names {
-			if name.IsExported() {
-				continue
-			}
-			typStr := toTypeString(field.Type)
-			if typStr == "" {
-				continue
-			}
 
-			exportName := upperNameSmart(name.Name)
-			g.Printf(getterFormat, fmt.Sprintf("%s%s", typeName, typeGenericSig), exportName, name.Name, typStr)
-			if g.withSetter {
-				g.Printf(setterFormat, fmt.Sprintf("%s%s", typeName, typeGenericSig), exportName, name.Name, typStr)
-			}
-			exist = true
+func relativePath(path string) string {
+	if wd, err := os.Getwd(); err == nil {
+		if rel, err := filepath.Rel(wd, path); err == nil && !strings.HasPrefix(rel, "..") {
+			return rel
 		}
 	}
+	return path
+}
 
-	if !exist {
-		log.Fatal("struct doesn't have private fields to generate accessors")
-	}
+// writer writes generated files, or in check mode compares them with the
+// files on disk and reports the difference.
+type writer struct {
+	check bool
+	stale int
 }
 
-func toTypeString(exp ast.Expr) string {
-	switch x := exp.(type) {
-	case *ast.Ident:
-		if x.Obj != nil {
-			typs := x.Obj.Decl.(*ast.TypeSpec)
-			if !typs.Name.IsExported() {
-				return ""
-			}
+func (w *writer) write(output string, data []byte) {
+	if w.check {
+		current, err := ioutil.ReadFile(output)
+		if err != nil && !os.IsNotExist(err) {
+			log.Fatalf("read file(%s) failed: %s", output, err)
 		}
-	case *ast.StarExpr:
-		if toTypeString(x.X) == "" {
-			return ""
-		}
-	case *ast.ArrayType:
-		if toTypeString(x.Elt) == "" {
-			return ""
-		}
-	case *ast.MapType:
-		if toTypeString(x.Key) == "" || toTypeString(x.Value) == "" {
-			return ""
-		}
-	case *ast.FuncType:
-		for _, f := range x.Params.List {
-			if toTypeString(f.Type) == "" {
-				return ""
-			}
-		}
-		for _, f := range x.Results.List {
-			if toTypeString(f.Type) == "" {
-				return ""
-			}
-		}
-	case *ast.StructType:
-		return ""
-	case *ast.SelectorExpr:
-	case *ast.IndexExpr:
-		if toTypeString(x.X) == "" || toTypeString(x.Index) == "" {
-			return ""
-		}
-	case *ast.IndexListExpr:
-		if toTypeString(x.X) == "" {
-			return ""
+		diff, err := unifiedDiff(output, output+" (generated)", current, data)
+		if err != nil {
+			log.Fatalf("diff file(%s) failed: %s", output, err)
 		}
-		for _, idx := range x.Indices {
-			if toTypeString(idx) == "" {
-				return ""
-			}
+		if diff != "" {
+			w.stale++
+			fmt.Fprintf(os.Stderr, "%s is out of date\n%s", output, diff)
 		}
-	default:
-		return ""
+		return
 	}
 
-	var buf bytes.Buffer
-	if err := format.Node(&buf, token.NewFileSet(), exp); err != nil {
-		log.Fatalf("format ast.Expr failed:%s", err)
-	}
-	return buf.String()
-}
-
-// -----------------------
-// smart upperName section
-// -----------------------
-
-var initialismsList = []string{
-	"ACL", "API", "ASCII", "CPU", "CSS", "DNS", "EOF", "GUID", "HTML", "HTTP", "HTTPS", "ID", "IP", "JSON",
-	"QPS", "RAM", "RPC", "SLA", "SMTP", "SQL", "SSH", "TCP", "TLS", "TTL", "UDP", "UI", "GID", "UID", "ULID",
-	"UUID", "URI", "URL", "UTF8", "VM", "XML", "XMPP", "XSRF", "XSS", "SIP", "RTP", "AMQP", "DB", "TS",
-}
-var initialismsMap = makeInitialismsMap(initialismsList)
-
-func makeInitialismsMap(list []string) map[string]string {
-	m := make(map[string]string, len(list))
-	for _, s := range list {
-		m[strings.ToLower(s)] = s
+	if err := ioutil.WriteFile(output, data, 0644); err != nil {
+		log.Fatalf("write file(%s) failed: %s", output, err)
 	}
-	return m
 }
 
-var camelCaseMatchParts = regexp.MustCompile(`[A-Z]+[a-z0-9]*|[a-z0-9]+`)
-
-func upperNameSmart(s string) string {
-	parts := camelCaseMatchParts.FindAllString(s, -1)
-	for i, p := range parts {
-		lower := strings.ToLower(p)
-		if v, ok := initialismsMap[lower]; ok {
-			parts[i] = v
-		} else {
-			parts[i] = cases.Title(language.English).String(p)
-		}
+func (w *writer) exit() {
+	if w.stale > 0 {
+		log.Fatalf("%d generated files are out of date", w.stale)
 	}
-	return strings.Join(parts, "")
-}
-
-// snake case converter
-var matchFirstCap = regexp.MustCompile("(.)([A-Z][a-z]+)")
-var matchAllCap = regexp.MustCompile("([a-z0-9])([A-Z])")
-
-func camelToSnake(s string) string {
-	snake := matchFirstCap.ReplaceAllString(s, "${1}_${2}")
-	snake = matchAllCap.ReplaceAllString(snake, "${1}_${2}")
-	return strings.ToLower(snake)
 }